@@ -0,0 +1,400 @@
+// Package analytics aggregates spending in a snapshot.
+//
+// Spending groups the expense transactions of a synchronization response by
+// merchant, payee, tag, or account over daily, weekly, or monthly periods and
+// converts every amount into a reporting currency with the instrument rates
+// of the snapshot:
+//
+//	table, err := analytics.Spending(snapshot, analytics.Options{
+//		GroupBy: analytics.ByMerchant,
+//		Period:  analytics.Monthly,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	for _, row := range table.SortByAmount().Top(10) {
+//		fmt.Println(row.Name, row.Amount)
+//	}
+//
+// An expense is a transaction that is not deleted, has a positive outcome, and
+// takes money from and to the same account. Transfers, income, and debt
+// operations are not counted.
+package analytics
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Dimension selects how expenses are grouped.
+type Dimension string
+
+const (
+	// ByMerchant groups by Transaction.Merchant, falling back to the payee.
+	ByMerchant Dimension = "merchant"
+	// ByPayee groups by the normalized Transaction.Payee.
+	ByPayee Dimension = "payee"
+	// ByTag groups by the first tag of a transaction, its category.
+	ByTag Dimension = "tag"
+	// ByAccount groups by the account the money was spent from.
+	ByAccount Dimension = "account"
+)
+
+// Period selects the length of the periods expenses are grouped into.
+type Period string
+
+const (
+	// Total puts all expenses into a single period.
+	Total   Period = ""
+	Daily   Period = "day"
+	Weekly  Period = "week"
+	Monthly Period = "month"
+)
+
+// Options controls Spending. Zero values select the defaults.
+type Options struct {
+	// GroupBy is the grouping dimension. It defaults to ByTag.
+	GroupBy Dimension
+
+	// Period is the grouping period. It defaults to Total.
+	Period Period
+
+	// From and To limit the transaction dates, both inclusive. Only their
+	// calendar dates in their own location are used, so a To of local
+	// midnight includes that day. Zero values leave the range open.
+	From time.Time
+	To   time.Time
+
+	// Currency is the instrument ID of the reporting currency. It defaults to
+	// the currency of the first user in the snapshot.
+	Currency int
+
+	// MonthStartDay is the day of the month monthly periods start on. It
+	// defaults to User.MonthStartDay of the first user, or 1.
+	MonthStartDay int
+
+	// WeekStart is the first day of weekly periods. It defaults to Monday.
+	WeekStart *time.Weekday
+}
+
+// Row is the spending of one group in one period.
+type Row struct {
+	// Period is the first day of the period, or the zero time for Total.
+	Period time.Time
+
+	// Key identifies the group: a merchant, tag, or account ID, or a
+	// normalized payee. It is empty for expenses without a merchant, payee,
+	// or tag.
+	Key string
+
+	// Name is the title of the merchant, tag, or account, or the payee as
+	// written in the first expense of the group.
+	Name string
+
+	// Amount is the spending in the reporting currency.
+	Amount float64
+
+	// Count is the number of expenses.
+	Count int
+}
+
+// Table is the result of Spending.
+type Table struct {
+	// Currency is the instrument ID amounts are reported in.
+	Currency int
+
+	Rows []Row
+
+	// opts are the options of Spending that built the table, used to find
+	// periods without spending.
+	opts Options
+}
+
+// Change compares the spending of a group with its previous period.
+type Change struct {
+	Period   time.Time
+	Key      string
+	Name     string
+	Amount   float64
+	Previous float64
+
+	// Delta is Amount minus Previous.
+	Delta float64
+
+	// Percent is Delta relative to Previous, or nil when Previous is zero.
+	Percent *float64
+}
+
+// Spending groups the expenses in snapshot according to opts. It returns an
+// ErrInvalidRequest error for unsupported options and for expenses in
+// instruments without a rate in the snapshot.
+func Spending(snapshot models.Response, opts Options) (*Table, error) {
+	opts, err := withDefaults(snapshot, opts)
+	if err != nil {
+		return nil, err
+	}
+	rates := make(map[int]float64, len(snapshot.Instrument))
+	for _, instrument := range snapshot.Instrument {
+		rates[instrument.ID] = instrument.Rate
+	}
+	if rates[opts.Currency] <= 0 {
+		return nil, invalid(fmt.Sprintf("reporting currency %d has no rate", opts.Currency))
+	}
+	names := names(snapshot)
+
+	type groupKey struct {
+		period time.Time
+		key    string
+	}
+	groups := make(map[groupKey]*Row)
+	for _, transaction := range snapshot.Transaction {
+		if !finance.IsExpense(transaction) {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, transaction.Date)
+		if err != nil {
+			return nil, errors.New(errors.ErrInvalidRequest, fmt.Sprintf("transaction %s has an invalid date", transaction.ID), err)
+		}
+		if (!opts.From.IsZero() && date.Before(opts.From)) || (!opts.To.IsZero() && date.After(opts.To)) {
+			continue
+		}
+		rate := rates[transaction.OutcomeInstrument]
+		if rate <= 0 {
+			return nil, invalid(fmt.Sprintf("instrument %d of transaction %s has no rate", transaction.OutcomeInstrument, transaction.ID))
+		}
+
+		key, name := groupOf(transaction, opts.GroupBy, names)
+		id := groupKey{period: periodStart(date, opts), key: key}
+		row := groups[id]
+		if row == nil {
+			row = &Row{Period: id.period, Key: key, Name: name}
+			groups[id] = row
+		}
+		row.Amount += transaction.Outcome * rate / rates[opts.Currency]
+		row.Count++
+	}
+
+	table := &Table{Currency: opts.Currency, opts: opts}
+	for _, row := range groups {
+		row.Amount = finance.Round(row.Amount)
+		table.Rows = append(table.Rows, *row)
+	}
+
+	return table.SortByPeriod(), nil
+}
+
+// SortByAmount sorts the rows by descending amount, then by key, and returns
+// the table.
+func (t *Table) SortByAmount() *Table {
+	slices.SortFunc(t.Rows, func(a, b Row) int {
+		return cmp.Or(cmp.Compare(b.Amount, a.Amount), cmp.Compare(a.Key, b.Key), a.Period.Compare(b.Period))
+	})
+
+	return t
+}
+
+// SortByPeriod sorts the rows by period, then by descending amount, and
+// returns the table.
+func (t *Table) SortByPeriod() *Table {
+	slices.SortFunc(t.Rows, func(a, b Row) int {
+		return cmp.Or(a.Period.Compare(b.Period), cmp.Compare(b.Amount, a.Amount), cmp.Compare(a.Key, b.Key))
+	})
+
+	return t
+}
+
+// Top returns at most the first n rows in the current order.
+func (t *Table) Top(n int) []Row {
+	return t.Rows[:min(max(n, 0), len(t.Rows))]
+}
+
+// Total returns the sum of all rows.
+func (t *Table) Total() float64 {
+	var total float64
+	for _, row := range t.Rows {
+		total += row.Amount
+	}
+
+	return finance.Round(total)
+}
+
+// Changes compares every group with its previous period, such as this month
+// with the previous month. Every period from the first to the last row is
+// compared, including periods without spending, and groups without spending
+// in a period are compared with zero. The first period of the table has no
+// previous period and is not reported. Tables not built by Spending only
+// compare the periods present in their rows.
+func (t *Table) Changes() []Change {
+	var periods []time.Time
+	amounts := make(map[time.Time]map[string]Row)
+	for _, row := range t.Rows {
+		if amounts[row.Period] == nil {
+			amounts[row.Period] = make(map[string]Row)
+			periods = append(periods, row.Period)
+		}
+		amounts[row.Period][row.Key] = row
+	}
+	slices.SortFunc(periods, time.Time.Compare)
+	if t.opts.Period != Total && len(periods) > 0 {
+		periods = periodRange(periods[0], periods[len(periods)-1], t.opts)
+	}
+
+	var changes []Change
+	for i := 1; i < len(periods); i++ {
+		current, previous := amounts[periods[i]], amounts[periods[i-1]]
+		keys := make(map[string]string)
+		for key, row := range previous {
+			keys[key] = row.Name
+		}
+		for key, row := range current {
+			keys[key] = row.Name
+		}
+		for key, name := range keys {
+			change := Change{Period: periods[i], Key: key, Name: name, Amount: current[key].Amount, Previous: previous[key].Amount}
+			change.Delta = finance.Round(change.Amount - change.Previous)
+			if change.Previous != 0 {
+				percent := finance.Round(change.Delta / change.Previous * 100)
+				change.Percent = &percent
+			}
+			changes = append(changes, change)
+		}
+	}
+	slices.SortFunc(changes, func(a, b Change) int {
+		return cmp.Or(a.Period.Compare(b.Period), cmp.Compare(b.Delta, a.Delta), cmp.Compare(a.Key, b.Key))
+	})
+
+	return changes
+}
+
+func withDefaults(snapshot models.Response, opts Options) (Options, error) {
+	if opts.GroupBy == "" {
+		opts.GroupBy = ByTag
+	}
+	if !slices.Contains([]Dimension{ByMerchant, ByPayee, ByTag, ByAccount}, opts.GroupBy) {
+		return opts, invalid(fmt.Sprintf("unsupported grouping %q", opts.GroupBy))
+	}
+	if !slices.Contains([]Period{Total, Daily, Weekly, Monthly}, opts.Period) {
+		return opts, invalid(fmt.Sprintf("unsupported period %q", opts.Period))
+	}
+	if opts.Currency == 0 {
+		if len(snapshot.User) == 0 {
+			return opts, invalid("reporting currency is required when the snapshot has no user")
+		}
+		opts.Currency = snapshot.User[0].Currency
+	}
+	if opts.MonthStartDay == 0 && len(snapshot.User) > 0 {
+		opts.MonthStartDay = snapshot.User[0].MonthStartDay
+	}
+	if opts.MonthStartDay <= 0 {
+		opts.MonthStartDay = 1
+	}
+	if opts.MonthStartDay > 31 {
+		return opts, invalid("month start day must be between 1 and 31")
+	}
+	if !opts.From.IsZero() {
+		opts.From = calendarDate(opts.From)
+	}
+	if !opts.To.IsZero() {
+		opts.To = calendarDate(opts.To)
+	}
+	if opts.WeekStart == nil {
+		monday := time.Monday
+		opts.WeekStart = &monday
+	}
+
+	return opts, nil
+}
+
+// names maps merchant, tag, and account IDs to their titles.
+func names(snapshot models.Response) map[string]string {
+	result := make(map[string]string, len(snapshot.Merchant)+len(snapshot.Tag)+len(snapshot.Account))
+	for _, merchant := range snapshot.Merchant {
+		result[merchant.ID] = merchant.Title
+	}
+	for _, tag := range snapshot.Tag {
+		result[tag.ID] = tag.Title
+	}
+	for _, account := range snapshot.Account {
+		result[account.ID] = account.Title
+	}
+
+	return result
+}
+
+func groupOf(transaction models.Transaction, dimension Dimension, names map[string]string) (key string, name string) {
+	switch dimension {
+	case ByMerchant:
+		if transaction.Merchant != nil && *transaction.Merchant != "" {
+			return *transaction.Merchant, names[*transaction.Merchant]
+		}
+		return finance.NormalizePayee(transaction.Payee), transaction.Payee
+	case ByPayee:
+		return finance.NormalizePayee(transaction.Payee), transaction.Payee
+	case ByTag:
+		if len(transaction.Tag) == 0 {
+			return "", ""
+		}
+		return transaction.Tag[0], names[transaction.Tag[0]]
+	default:
+		return *transaction.OutcomeAccount, names[*transaction.OutcomeAccount]
+	}
+}
+
+// periodStart returns the first day of the period containing date.
+func periodStart(date time.Time, opts Options) time.Time {
+	switch opts.Period {
+	case Daily:
+		return date
+	case Weekly:
+		offset := (int(date.Weekday()) - int(*opts.WeekStart) + 7) % 7
+		return date.AddDate(0, 0, -offset)
+	case Monthly:
+		start := finance.MonthDay(date.Year(), date.Month(), opts.MonthStartDay, time.UTC)
+		if date.Before(start) {
+			start = finance.MonthDay(date.Year(), date.Month()-1, opts.MonthStartDay, time.UTC)
+		}
+		return start
+	default:
+		return time.Time{}
+	}
+}
+
+// periodRange returns the starts of the periods from first to last, both
+// inclusive.
+func periodRange(first, last time.Time, opts Options) []time.Time {
+	var periods []time.Time
+	for start := first; !start.After(last); start = nextPeriod(start, opts) {
+		periods = append(periods, start)
+	}
+
+	return periods
+}
+
+// nextPeriod returns the first day of the period after the one starting on
+// start.
+func nextPeriod(start time.Time, opts Options) time.Time {
+	switch opts.Period {
+	case Daily:
+		return start.AddDate(0, 0, 1)
+	case Weekly:
+		return start.AddDate(0, 0, 7)
+	default:
+		return finance.MonthDay(start.Year(), start.Month()+1, opts.MonthStartDay, time.UTC)
+	}
+}
+
+// calendarDate returns the calendar date of t at midnight UTC, the form
+// transaction dates are parsed into.
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func invalid(message string) error {
+	return errors.New(errors.ErrInvalidRequest, message, nil)
+}
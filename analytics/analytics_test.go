@@ -0,0 +1,166 @@
+package analytics_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/analytics"
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/txtest"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rub = 1
+	usd = 2
+)
+
+func snapshot() models.Response {
+	shop := "shop"
+	withMerchant := txtest.Expense("t4", "2024-02-10", 10, txtest.InInstrument(usd), txtest.Tags("food"), txtest.Payee("SHOP #1"))
+	withMerchant.Merchant = &shop
+	savings := "savings"
+	transfer := txtest.Expense("t6", "2024-02-11", 500)
+	transfer.IncomeAccount = savings
+
+	return models.Response{
+		Instrument: []models.Instrument{{ID: rub, Rate: 1}, {ID: usd, Rate: 90}},
+		User:       []models.User{{ID: 1, Currency: rub, MonthStartDay: 10}},
+		Tag:        []models.Tag{{ID: "food", Title: "Food"}, {ID: "fun", Title: "Fun"}},
+		Merchant:   []models.Merchant{{ID: shop, Title: "Shop"}},
+		Account:    []models.Account{{ID: "card", Title: "Card"}},
+		Transaction: []models.Transaction{
+			txtest.Expense("t1", "2024-01-09", 100, txtest.Tags("food"), txtest.Payee("Cafe")),
+			txtest.Expense("t2", "2024-01-10", 200, txtest.Tags("food"), txtest.Payee("cafe ")),
+			txtest.Expense("t3", "2024-01-15", 300, txtest.Tags("fun"), txtest.Payee("Cinema")),
+			withMerchant,
+			txtest.Expense("t5", "2024-02-09", 50, txtest.Payee("Cafe")),
+			transfer,
+		},
+	}
+}
+
+func TestSpendingByTagMonthly(t *testing.T) {
+	table, err := analytics.Spending(snapshot(), analytics.Options{Period: analytics.Monthly})
+	require.NoError(t, err)
+
+	require.Equal(t, rub, table.Currency)
+	require.Equal(t, []analytics.Row{
+		{Period: date("2023-12-10"), Key: "food", Name: "Food", Amount: 100, Count: 1},
+		{Period: date("2024-01-10"), Key: "fun", Name: "Fun", Amount: 300, Count: 1},
+		{Period: date("2024-01-10"), Key: "food", Name: "Food", Amount: 200, Count: 1},
+		{Period: date("2024-01-10"), Key: "", Name: "", Amount: 50, Count: 1},
+		{Period: date("2024-02-10"), Key: "food", Name: "Food", Amount: 900, Count: 1},
+	}, table.Rows)
+	require.Equal(t, 1550.0, table.Total())
+
+	changes := table.Changes()
+	require.Len(t, changes, 6)
+	require.Equal(t, date("2024-01-10"), changes[0].Period)
+	require.Equal(t, "fun", changes[0].Key)
+	require.Equal(t, 300.0, changes[0].Delta)
+	require.Nil(t, changes[0].Percent)
+	require.Equal(t, analytics.Change{Period: date("2024-02-10"), Key: "food", Name: "Food", Amount: 900, Previous: 200, Delta: 700, Percent: percent(350)}, changes[3])
+	last := changes[len(changes)-1]
+	require.Equal(t, "fun", last.Key)
+	require.Equal(t, -300.0, last.Delta)
+	require.Equal(t, -100.0, *last.Percent)
+}
+
+func TestChangesIncludePeriodsWithoutSpending(t *testing.T) {
+	table, err := analytics.Spending(models.Response{
+		Instrument: []models.Instrument{{ID: rub, Rate: 1}},
+		User:       []models.User{{ID: 1, Currency: rub}},
+		Transaction: []models.Transaction{
+			txtest.Expense("jan", "2024-01-15", 100, txtest.Tags("food")),
+			txtest.Expense("mar", "2024-03-20", 40, txtest.Tags("food")),
+		},
+	}, analytics.Options{Period: analytics.Monthly})
+	require.NoError(t, err)
+
+	changes := table.Changes()
+
+	require.Equal(t, []analytics.Change{
+		{Period: date("2024-02-01"), Key: "food", Amount: 0, Previous: 100, Delta: -100, Percent: percent(-100)},
+		{Period: date("2024-03-01"), Key: "food", Amount: 40, Previous: 0, Delta: 40},
+	}, changes)
+
+	encoded, err := json.Marshal(changes)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"Percent":null`)
+}
+
+func TestSpendingByMerchantTop(t *testing.T) {
+	table, err := analytics.Spending(snapshot(), analytics.Options{
+		GroupBy:  analytics.ByMerchant,
+		Currency: usd,
+		From:     date("2024-01-10"),
+	})
+	require.NoError(t, err)
+
+	top := table.SortByAmount().Top(2)
+	require.Equal(t, []analytics.Row{
+		{Key: "shop", Name: "Shop", Amount: 10, Count: 1},
+		{Key: "cinema", Name: "Cinema", Amount: 3.33, Count: 1},
+	}, top)
+	require.Equal(t, analytics.Row{Key: "cafe", Name: "cafe ", Amount: 2.78, Count: 2}, table.Rows[2])
+	require.Empty(t, table.Top(-1))
+}
+
+func TestSpendingWeekly(t *testing.T) {
+	table, err := analytics.Spending(snapshot(), analytics.Options{
+		GroupBy: analytics.ByAccount,
+		Period:  analytics.Weekly,
+		To:      date("2024-01-15"),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []analytics.Row{
+		{Period: date("2024-01-08"), Key: "card", Name: "Card", Amount: 300, Count: 2},
+		{Period: date("2024-01-15"), Key: "card", Name: "Card", Amount: 300, Count: 1},
+	}, table.Rows)
+}
+
+func TestSpendingRangeUsesCalendarDates(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	table, err := analytics.Spending(snapshot(), analytics.Options{
+		From: time.Date(2024, time.January, 10, 23, 30, 0, 0, time.UTC),
+		To:   time.Date(2024, time.January, 15, 0, 0, 0, 0, moscow),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []analytics.Row{
+		{Key: "fun", Name: "Fun", Amount: 300, Count: 1},
+		{Key: "food", Name: "Food", Amount: 200, Count: 1},
+	}, table.Rows)
+}
+
+func TestSpendingRejectsInvalidOptions(t *testing.T) {
+	missingRate := snapshot()
+	missingRate.Instrument = missingRate.Instrument[:1]
+
+	for _, tc := range []struct {
+		snapshot models.Response
+		opts     analytics.Options
+	}{
+		{snapshot(), analytics.Options{GroupBy: "weekday"}},
+		{snapshot(), analytics.Options{Period: "quarter"}},
+		{missingRate, analytics.Options{}},
+	} {
+		_, err := analytics.Spending(tc.snapshot, tc.opts)
+		var sdkErr *sdkerrors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, sdkerrors.ErrInvalidRequest, sdkErr.Code)
+	}
+}
+
+func percent(value float64) *float64 {
+	return &value
+}
+
+func date(value string) time.Time {
+	result, _ := time.Parse(time.DateOnly, value)
+	return result
+}
@@ -0,0 +1,257 @@
+// Package anomaly flags transactions with unusual amounts.
+//
+// Detect compares every candidate expense with the expenses that precede it
+// in a rolling window. For the candidate's tag and for its merchant or payee
+// it computes the median and the median absolute deviation (MAD) of past
+// amounts in the same currency and reports amounts whose robust z-score
+//
+//	0.6745 * (amount - median) / MAD
+//
+// exceeds the threshold. The median and MAD are insensitive to the occasional
+// large purchase in the history, unlike the mean and standard deviation.
+// Payments to merchants never seen before and spending in a foreign currency,
+// recognized by Transaction.OpOutcomeInstrument, are reported as well.
+package anomaly
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Kind identifies the signal that flagged a transaction.
+type Kind string
+
+const (
+	// KindTagAmount means the amount is unusual for the transaction's tag.
+	KindTagAmount Kind = "tag_amount"
+	// KindMerchantAmount means the amount is unusual for the merchant or
+	// payee.
+	KindMerchantAmount Kind = "merchant_amount"
+	// KindNewMerchant means no earlier expense has the merchant or payee.
+	// It is only reported once the history has MinHistory expenses.
+	KindNewMerchant Kind = "new_merchant"
+	// KindForeignCurrency means the expense was made in a foreign currency
+	// that was never used before or with an unusual amount in that currency.
+	// First-time currencies are gated like KindNewMerchant.
+	KindForeignCurrency Kind = "foreign_currency"
+)
+
+// Options controls detection. Zero values select the defaults.
+type Options struct {
+	// Window is the number of days before a candidate whose expenses form its
+	// history. It defaults to 90 days. New merchants are checked against the
+	// whole history regardless of the window.
+	Window int
+
+	// Threshold is the robust z-score above which an amount is reported. It
+	// defaults to 3.5.
+	Threshold float64
+
+	// MinHistory is the number of past amounts required before amounts of a
+	// tag, merchant, or currency are scored, and the number of earlier
+	// expenses required before first-time merchants and currencies are
+	// reported. It defaults to 5.
+	MinHistory int
+}
+
+// Anomaly is one signal raised for a transaction.
+type Anomaly struct {
+	Transaction models.Transaction
+	Kind        Kind
+
+	// Key is the tag ID, merchant ID, normalized payee, or instrument ID the
+	// history was grouped by, formatted as a string.
+	Key string
+
+	// Amount is the scored amount: Outcome, or OpOutcome for
+	// KindForeignCurrency.
+	Amount float64
+
+	// Score is the robust z-score. It is zero for first-time merchants and
+	// currencies.
+	Score float64
+
+	// Median and MAD describe the history the amount was compared with.
+	Median float64
+	MAD    float64
+}
+
+func (o Options) withDefaults() Options {
+	if o.Window <= 0 {
+		o.Window = 90
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = 3.5
+	}
+	if o.MinHistory <= 0 {
+		o.MinHistory = 5
+	}
+
+	return o
+}
+
+// Detect checks candidates against history and returns the anomalies in
+// candidate order. Only expenses are considered: deleted transactions,
+// transfers, and income are ignored on both sides. Candidates may also be part
+// of history; only expenses dated before a candidate count as its history.
+func Detect(history []models.Transaction, candidates []models.Transaction, opts Options) []Anomaly {
+	opts = opts.withDefaults()
+
+	past := make([]expense, 0, len(history))
+	for _, transaction := range history {
+		if e, ok := newExpense(transaction); ok {
+			past = append(past, e)
+		}
+	}
+	slices.SortStableFunc(past, func(a, b expense) int {
+		return a.date.Compare(b.date)
+	})
+
+	var result []Anomaly
+	for _, transaction := range candidates {
+		candidate, ok := newExpense(transaction)
+		if !ok {
+			continue
+		}
+		before := past[:firstOnOrAfter(past, candidate.date)]
+		windowStart := candidate.date.AddDate(0, 0, -opts.Window)
+		window := before[firstOnOrAfter(before, windowStart):]
+
+		result = append(result, check(candidate, before, window, opts)...)
+	}
+
+	return result
+}
+
+func check(candidate expense, before []expense, window []expense, opts Options) []Anomaly {
+	var result []Anomaly
+	flag := func(kind Kind, key string, amount float64, amounts []float64) {
+		if len(amounts) < opts.MinHistory {
+			return
+		}
+		median, mad := robust(amounts)
+		if score := 0.6745 * (amount - median) / mad; score > opts.Threshold {
+			result = append(result, Anomaly{
+				Transaction: candidate.transaction, Kind: kind, Key: key,
+				Amount: amount, Score: finance.Round(score), Median: median, MAD: finance.Round(mad),
+			})
+		}
+	}
+	amounts := func(match func(expense) bool, value func(expense) float64) []float64 {
+		var values []float64
+		for _, e := range window {
+			if match(e) {
+				values = append(values, value(e))
+			}
+		}
+		return values
+	}
+	outcome := func(e expense) float64 { return e.transaction.Outcome }
+	sameInstrument := func(e expense) bool {
+		return e.transaction.OutcomeInstrument == candidate.transaction.OutcomeInstrument
+	}
+
+	if candidate.tag != "" {
+		flag(KindTagAmount, candidate.tag, candidate.transaction.Outcome, amounts(func(e expense) bool {
+			return e.tag == candidate.tag && sameInstrument(e)
+		}, outcome))
+	}
+	// First-time merchants and currencies are only unusual once there is
+	// enough history to know the usual ones.
+	established := len(before) >= opts.MinHistory
+	if candidate.merchant != "" {
+		switch {
+		case slices.ContainsFunc(before, func(e expense) bool { return e.merchant == candidate.merchant }):
+			flag(KindMerchantAmount, candidate.merchant, candidate.transaction.Outcome, amounts(func(e expense) bool {
+				return e.merchant == candidate.merchant && sameInstrument(e)
+			}, outcome))
+		case established:
+			result = append(result, Anomaly{
+				Transaction: candidate.transaction, Kind: KindNewMerchant, Key: candidate.merchant,
+				Amount: candidate.transaction.Outcome,
+			})
+		}
+	}
+	if instrument := candidate.foreign; instrument != 0 {
+		key := strconv.Itoa(instrument)
+		amount := candidate.transaction.OpOutcome
+		switch {
+		case slices.ContainsFunc(before, func(e expense) bool { return e.foreign == instrument }):
+			flag(KindForeignCurrency, key, amount, amounts(func(e expense) bool {
+				return e.foreign == instrument
+			}, func(e expense) float64 { return e.transaction.OpOutcome }))
+		case established:
+			result = append(result, Anomaly{Transaction: candidate.transaction, Kind: KindForeignCurrency, Key: key, Amount: amount})
+		}
+	}
+
+	return result
+}
+
+// expense is a parsed expense transaction.
+type expense struct {
+	transaction models.Transaction
+	date        time.Time
+	tag         string
+	merchant    string
+
+	// foreign is the operation instrument when it differs from the account
+	// instrument, and zero otherwise.
+	foreign int
+}
+
+func newExpense(transaction models.Transaction) (expense, bool) {
+	if !finance.IsExpense(transaction) {
+		return expense{}, false
+	}
+	date, err := time.Parse(time.DateOnly, transaction.Date)
+	if err != nil {
+		return expense{}, false
+	}
+
+	e := expense{transaction: transaction, date: date, merchant: finance.NormalizePayee(transaction.Payee)}
+	if transaction.Merchant != nil && *transaction.Merchant != "" {
+		e.merchant = *transaction.Merchant
+	}
+	if len(transaction.Tag) > 0 {
+		e.tag = transaction.Tag[0]
+	}
+	if instrument := transaction.OpOutcomeInstrument; instrument != nil && *instrument > 0 &&
+		*instrument != transaction.OutcomeInstrument && transaction.OpOutcome > 0 {
+		e.foreign = *instrument
+	}
+
+	return e, true
+}
+
+// firstOnOrAfter returns the index of the first expense dated on or after
+// date in expenses sorted by date.
+func firstOnOrAfter(expenses []expense, date time.Time) int {
+	i, _ := slices.BinarySearchFunc(expenses, date, func(e expense, date time.Time) int {
+		return e.date.Compare(date)
+	})
+
+	return i
+}
+
+// robust returns the median and the median absolute deviation of values.
+// A zero deviation, when most past amounts are equal, is replaced with 5% of
+// the median so that a repeated amount does not make every change an anomaly.
+func robust(values []float64) (median float64, mad float64) {
+	median = finance.Median(values)
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - median)
+	}
+	mad = finance.Median(deviations)
+	if mad == 0 {
+		mad = max(math.Abs(median)*0.05, 0.01)
+	}
+
+	return median, mad
+}
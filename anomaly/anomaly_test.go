@@ -0,0 +1,97 @@
+package anomaly_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/anomaly"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/txtest"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	usd = 2
+	eur = 3
+)
+
+func history() []models.Transaction {
+	var result []models.Transaction
+	for i, amount := range []float64{480, 520, 500, 510, 490, 505, 5000} {
+		result = append(result, txtest.Expense(fmt.Sprintf("g%d", i), fmt.Sprintf("2024-03-%02d", i+1), amount, txtest.Tags("groceries"), txtest.Payee("Market")))
+	}
+	for i, amount := range []float64{100, 120, 90, 110, 105} {
+		result = append(result, txtest.Expense(fmt.Sprintf("f%d", i), fmt.Sprintf("2024-03-%02d", i+10), amount*100, txtest.Tags("travel"), txtest.Payee("Hotel"), txtest.Paid(amount, eur)))
+	}
+
+	return result
+}
+
+func kinds(anomalies []anomaly.Anomaly) map[string][]anomaly.Kind {
+	result := make(map[string][]anomaly.Kind)
+	for _, a := range anomalies {
+		result[a.Transaction.ID] = append(result[a.Transaction.ID], a.Kind)
+	}
+
+	return result
+}
+
+func TestDetect(t *testing.T) {
+	usual := txtest.Expense("c1", "2024-04-01", 530, txtest.Tags("groceries"), txtest.Payee("Market"))
+	large := txtest.Expense("c2", "2024-04-01", 1500, txtest.Tags("groceries"), txtest.Payee("Market"))
+	newShop := txtest.Expense("c3", "2024-04-01", 500, txtest.Tags("groceries"), txtest.Payee("Fancy Deli"))
+	travel := txtest.Expense("c4", "2024-04-01", 90000, txtest.Tags("travel"), txtest.Payee("Hotel"), txtest.Paid(900, eur))
+	firstDollar := txtest.Expense("c5", "2024-04-01", 100, txtest.Tags("groceries"), txtest.Payee("Market"), txtest.Paid(1, usd))
+
+	anomalies := anomaly.Detect(history(), []models.Transaction{usual, large, newShop, travel, firstDollar}, anomaly.Options{})
+
+	require.Equal(t, map[string][]anomaly.Kind{
+		"c2": {anomaly.KindTagAmount, anomaly.KindMerchantAmount},
+		"c3": {anomaly.KindNewMerchant},
+		"c4": {anomaly.KindTagAmount, anomaly.KindMerchantAmount, anomaly.KindForeignCurrency},
+		"c5": {anomaly.KindForeignCurrency},
+	}, kinds(anomalies))
+
+	tagAnomaly := anomalies[0]
+	require.Equal(t, "groceries", tagAnomaly.Key)
+	require.Equal(t, 1500.0, tagAnomaly.Amount)
+	require.Equal(t, 505.0, tagAnomaly.Median)
+	require.Equal(t, 15.0, tagAnomaly.MAD)
+	require.Greater(t, tagAnomaly.Score, 40.0)
+
+	foreignAnomaly := anomalies[len(anomalies)-2]
+	require.Equal(t, anomaly.KindForeignCurrency, foreignAnomaly.Kind)
+	require.Equal(t, "3", foreignAnomaly.Key)
+	require.Equal(t, 900.0, foreignAnomaly.Amount)
+	require.Equal(t, 105.0, foreignAnomaly.Median)
+}
+
+func TestDetectUsesRollingWindow(t *testing.T) {
+	candidate := txtest.Expense("late", "2024-09-01", 1500, txtest.Tags("groceries"), txtest.Payee("Market"))
+
+	require.Empty(t, anomaly.Detect(history(), []models.Transaction{candidate}, anomaly.Options{}))
+	require.Len(t, anomaly.Detect(history(), []models.Transaction{candidate}, anomaly.Options{Window: 365}), 2)
+}
+
+func TestDetectIgnoresLaterHistory(t *testing.T) {
+	all := history()
+	firstTrip := all[7]
+
+	require.Equal(t, map[string][]anomaly.Kind{
+		"f0": {anomaly.KindNewMerchant, anomaly.KindForeignCurrency},
+	}, kinds(anomaly.Detect(all, []models.Transaction{firstTrip}, anomaly.Options{})))
+}
+
+func TestDetectNeedsHistoryForFirstTimeSignals(t *testing.T) {
+	fresh := []models.Transaction{
+		txtest.Expense("n1", "2024-03-01", 500, txtest.Tags("groceries"), txtest.Payee("Market")),
+		txtest.Expense("n2", "2024-03-02", 300, txtest.Tags("cafe"), txtest.Payee("Coffee")),
+		txtest.Expense("n3", "2024-03-03", 9000, txtest.Tags("travel"), txtest.Payee("Hotel"), txtest.Paid(90, eur)),
+	}
+
+	require.Empty(t, anomaly.Detect(fresh, fresh, anomaly.Options{}))
+	require.Equal(t, map[string][]anomaly.Kind{
+		"n2": {anomaly.KindNewMerchant},
+		"n3": {anomaly.KindNewMerchant, anomaly.KindForeignCurrency},
+	}, kinds(anomaly.Detect(fresh, fresh, anomaly.Options{MinHistory: 1})))
+}
@@ -0,0 +1,10 @@
+package api
+
+import "github.com/nemirlev/zenmoney-go-sdk/v3/internal/client"
+
+// Clock provides the current time to the client. Implementations must be safe
+// for concurrent use.
+type Clock = client.Clock
+
+// SystemClock is the default Clock; it returns time.Now().
+type SystemClock = client.SystemClock
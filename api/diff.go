@@ -16,8 +16,9 @@ func (c *Client) Sync(ctx context.Context, body models.Request) (models.Response
 }
 
 // FullSync retrieves all data available to the authenticated user. It sends a
-// zero server timestamp and uses the current time as the client timestamp. Large
-// accounts may need a higher response limit configured with WithMaxResponseSize.
+// zero server timestamp and uses the current time of the client Clock as the
+// client timestamp. Large accounts may need a higher response limit configured
+// with WithMaxResponseSize.
 func (c *Client) FullSync(ctx context.Context) (models.Response, error) {
 	return c.internal.FullSync(ctx)
 }
@@ -34,7 +35,7 @@ func (c *Client) SyncSince(ctx context.Context, lastSync time.Time) (models.Resp
 //
 // Deprecated: Use ForceSyncEntitiesSince to include changes since a known server timestamp.
 func (c *Client) ForceSyncEntities(ctx context.Context, entityTypes ...models.EntityType) (models.Response, error) {
-	return c.internal.ForceSyncEntitiesSince(ctx, time.Now(), entityTypes...)
+	return c.internal.ForceSyncEntities(ctx, entityTypes...)
 }
 
 // ForceSyncEntitiesSince requests complete snapshots of entityTypes together
@@ -144,3 +144,43 @@ func jsonResponse(body string) *http.Response {
 		Header:     make(http.Header),
 	}
 }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestWithClockStampsRequests(t *testing.T) {
+	var bodies []string
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+
+			return jsonResponse(`{"serverTimestamp":1718454660}`), nil
+		}),
+	}
+	now := time.Unix(1_718_454_600, 0)
+	client, err := api.NewClient(
+		"test-token",
+		api.WithHTTPClient(httpClient),
+		api.WithRetryPolicy(0, 0),
+		api.WithClock(fixedClock(now)),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.FullSync(ctx)
+	require.NoError(t, err)
+	_, err = client.FullSync(ctx)
+	require.NoError(t, err)
+	_, err = client.ForceSyncEntities(ctx, models.EntityTypeTag) //nolint:staticcheck // Verify the deprecated cursor uses the clock.
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 3)
+	require.Equal(t, bodies[0], bodies[1])
+	require.JSONEq(t, `{"currentClientTimestamp":1718454600,"serverTimestamp":0}`, bodies[0])
+	require.JSONEq(t, `{"currentClientTimestamp":1718454600,"serverTimestamp":1718454600,"forceFetch":["tag"]}`, bodies[2])
+}
@@ -12,7 +12,18 @@ const (
 	ErrNetworkError     = sdkerrors.ErrNetworkError
 	ErrRateLimit        = sdkerrors.ErrRateLimit
 	ErrResponseTooLarge = sdkerrors.ErrResponseTooLarge
+	ErrStorage          = sdkerrors.ErrStorage
+	ErrCircuitOpen      = sdkerrors.ErrCircuitOpen
 )
 
 // Error describes an error returned by the SDK.
 type Error = sdkerrors.Error
+
+// BatchError reports the chunks of a batch operation that failed.
+type BatchError = sdkerrors.BatchError
+
+// ValidationError lists the invalid fields of an entity.
+type ValidationError = sdkerrors.ValidationError
+
+// FieldError describes one invalid field of an entity.
+type FieldError = sdkerrors.FieldError
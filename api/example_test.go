@@ -2,6 +2,7 @@ package api_test
 
 import (
 	"context"
+	"errors"
 	"log"
 	"log/slog"
 	"os"
@@ -140,3 +141,27 @@ func ExampleClient_SuggestBatch() {
 
 	_ = suggestions
 }
+
+func ExampleClient_SuggestAll() {
+	client, err := api.NewClient(
+		os.Getenv("ZENMONEY_TOKEN"),
+		api.WithSuggestChunking(50, 2),
+	)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	suggestions, err := client.SuggestAll(context.Background(), []models.Transaction{
+		{Payee: "Coffee shop"},
+		{Payee: "Grocery store"},
+	})
+	var batchErr *api.BatchError
+	if errors.As(err, &batchErr) {
+		log.Printf("%d chunks failed", len(batchErr.Chunks))
+	} else if err != nil {
+		log.Print(err)
+		return
+	}
+	_ = suggestions
+}
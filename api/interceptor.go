@@ -0,0 +1,17 @@
+package api
+
+import "github.com/nemirlev/zenmoney-go-sdk/v3/internal/client"
+
+// Call describes one attempt of an API operation. Request holds the value
+// encoded as the request body and Response points to the value the response
+// is decoded into, so interceptors work with SDK types instead of JSON.
+type Call = client.Call
+
+// Handler performs a call and returns an *Error on failure.
+type Handler = client.Handler
+
+// Interceptor wraps every attempt of an API operation. It receives the call
+// and the next handler in the chain and may audit or modify call.Request, skip
+// next and fill call.Response to answer from a cache, or inspect the decoded
+// response and the returned error, for example to record metrics.
+type Interceptor = client.Interceptor
@@ -0,0 +1,13 @@
+package api
+
+import "github.com/nemirlev/zenmoney-go-sdk/v3/internal/client"
+
+// Metrics receives measurements of HTTP attempts: completed requests by
+// endpoint, outcome and error code, attempt latency, bytes received, scheduled
+// retries, and rate-limit responses. Implement it to export the measurements to
+// a monitoring system such as Prometheus. Implementations must be safe for
+// concurrent use.
+type Metrics = client.Metrics
+
+// NopMetrics is a Metrics implementation that discards all measurements.
+type NopMetrics = client.NopMetrics
@@ -18,6 +18,21 @@ type Config struct {
 	retryWaitTime   time.Duration
 	maxResponseSize int64
 	logger          *slog.Logger
+	metrics         Metrics
+	clock           Clock
+
+	suggestChunkSize   int
+	suggestConcurrency int
+
+	interceptors          []Interceptor
+	operationInterceptors []Interceptor
+
+	breakerEnabled   bool
+	breakerThreshold int
+	breakerCooldown  time.Duration
+
+	compressRequests     bool
+	compressionThreshold int
 }
 
 // Option represents a function for configuring the client
@@ -33,6 +48,9 @@ func defaultConfig() *Config {
 		retryWaitTime:   1 * time.Second,
 		maxResponseSize: DefaultMaxResponseSize,
 		logger:          slog.New(slog.DiscardHandler),
+
+		suggestChunkSize:   100,
+		suggestConcurrency: 4,
 	}
 }
 
@@ -89,3 +107,82 @@ func WithLogger(logger *slog.Logger) Option {
 		c.logger = logger
 	}
 }
+
+// WithMetrics reports request counts, attempt latency, received bytes,
+// scheduled retries, and rate-limit responses to metrics. Metrics are
+// discarded by default; passing nil restores that behavior.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Config) {
+		c.metrics = metrics
+	}
+}
+
+// WithClock sets the clock the client reads when it stamps requests:
+// CurrentClientTimestamp in synchronization requests, the cursor of
+// ForceSyncEntities, Retry-After dates, and circuit breaker cooldowns. A fixed
+// clock makes request bodies reproducible in tests and replay tools. Request
+// durations are measured with the monotonic system clock, and retry waits use
+// real timers. Passing nil restores the system clock. The same Clock can be
+// passed to models.WithClock and file.WithClock.
+func WithClock(clock Clock) Option {
+	return func(c *Config) {
+		c.clock = clock
+	}
+}
+
+// WithSuggestChunking configures how SuggestAll splits large inputs. Each
+// request contains at most chunkSize transactions, and at most concurrency
+// requests run at the same time. Both values must be positive. The defaults
+// are 100 transactions per chunk and 4 concurrent requests.
+func WithSuggestChunking(chunkSize int, concurrency int) Option {
+	return func(c *Config) {
+		c.suggestChunkSize = chunkSize
+		c.suggestConcurrency = concurrency
+	}
+}
+
+// WithInterceptors adds interceptors that run around every request attempt,
+// including retries. Interceptors run in the order they are given, so the
+// first one sees the call first and the response last. Repeated options
+// append to the chain; nil interceptors are rejected by NewClient.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Config) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithOperationInterceptors adds interceptors that run once per SDK operation,
+// around all attempts and retry waits. When next returns, call.Attempt holds
+// the number of attempts made and the response metadata describes the last
+// one. Answering from a cache here skips the retry loop entirely.
+func WithOperationInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Config) {
+		c.operationInterceptors = append(c.operationInterceptors, interceptors...)
+	}
+}
+
+// WithCircuitBreaker stops sending requests after threshold consecutive
+// ErrServerError or ErrNetworkError attempts. While the circuit is open,
+// requests fail immediately with ErrCircuitOpen. After cooldown a single trial
+// request is sent: success closes the circuit and failure opens it again.
+// State changes are reported through the configured logger. Both values must
+// be positive. The breaker is disabled by default.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Config) {
+		c.breakerEnabled = true
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
+
+// WithRequestCompression gzips request bodies of at least threshold bytes and
+// sends them with Content-Encoding: gzip. Enable it only when the server
+// accepts compressed requests. threshold must be positive. Responses are
+// always requested with Accept-Encoding: gzip and decompressed transparently;
+// WithMaxResponseSize limits the decompressed size.
+func WithRequestCompression(threshold int) Option {
+	return func(c *Config) {
+		c.compressRequests = true
+		c.compressionThreshold = threshold
+	}
+}
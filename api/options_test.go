@@ -9,6 +9,7 @@ import (
 	"math"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -50,6 +51,12 @@ func TestClientOptionsValidation(t *testing.T) {
 			name: "negative retry wait",
 			opts: []api.Option{api.WithRetryPolicy(1, -time.Second)},
 		},
+		{name: "zero suggest chunk size", opts: []api.Option{api.WithSuggestChunking(0, 1)}},
+		{name: "zero suggest concurrency", opts: []api.Option{api.WithSuggestChunking(1, 0)}},
+		{name: "nil interceptor", opts: []api.Option{api.WithInterceptors(nil)}},
+		{name: "zero circuit breaker threshold", opts: []api.Option{api.WithCircuitBreaker(0, time.Second)}},
+		{name: "zero circuit breaker cooldown", opts: []api.Option{api.WithCircuitBreaker(1, 0)}},
+		{name: "zero compression threshold", opts: []api.Option{api.WithRequestCompression(0)}},
 	}
 
 	for _, tt := range tests {
@@ -164,3 +171,185 @@ func TestWithNilLoggerDisablesDiagnostics(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, client)
 }
+
+func TestWithInterceptorsWrapsRequests(t *testing.T) {
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return jsonResponse(`{"serverTimestamp":1718456000}`), nil
+		}),
+	}
+	var audit []string
+	auditor := func(ctx context.Context, call *api.Call, next api.Handler) error {
+		err := next(ctx, call)
+		audit = append(audit, call.Endpoint)
+		return err
+	}
+	cache := func(ctx context.Context, call *api.Call, next api.Handler) error {
+		if call.Endpoint == "suggest/" {
+			*call.Response.(*models.Transaction) = models.Transaction{Payee: "Cached"}
+			return nil
+		}
+		return next(ctx, call)
+	}
+	client, err := api.NewClient(
+		"test-token",
+		api.WithHTTPClient(httpClient),
+		api.WithInterceptors(auditor),
+		api.WithInterceptors(cache),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.FullSync(context.Background())
+	require.NoError(t, err)
+	suggestion, err := client.Suggest(context.Background(), models.Transaction{Payee: "Coffee"})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(1718456000), resp.ServerTimestamp)
+	require.Equal(t, "Cached", suggestion.Payee)
+	require.Equal(t, []string{"diff/", "suggest/"}, audit)
+}
+
+type recordingMetrics struct {
+	mu        sync.Mutex
+	events    []string
+	durations []time.Duration
+	bytes     int64
+	waits     []time.Duration
+	limited   []time.Duration
+}
+
+func (m *recordingMetrics) RequestCompleted(endpoint string, outcome string, code api.ErrorCode, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, endpoint+" "+outcome+" "+string(code))
+	m.durations = append(m.durations, duration)
+}
+
+func (m *recordingMetrics) BytesReceived(_ string, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes += bytes
+}
+
+func (m *recordingMetrics) RetryScheduled(_ string, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.waits = append(m.waits, wait)
+}
+
+func (m *recordingMetrics) RateLimited(_ string, retryAfter time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limited = append(m.limited, retryAfter)
+}
+
+func TestWithMetricsRecordsAttempts(t *testing.T) {
+	attempts := 0
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			switch attempts {
+			case 1:
+				return nil, stdErrors.New("temporary failure")
+			case 2:
+				return jsonResponse(`{"serverTimestamp":1}`), nil
+			default:
+				return &http.Response{
+					StatusCode: http.StatusTooManyRequests,
+					Header:     http.Header{"Retry-After": []string{"30"}},
+					Body:       io.NopCloser(strings.NewReader(`slow down`)),
+				}, nil
+			}
+		}),
+	}
+	metrics := &recordingMetrics{}
+	client, err := api.NewClient(
+		"test-token",
+		api.WithHTTPClient(httpClient),
+		api.WithRetryPolicy(1, 0),
+		api.WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+
+	_, err = client.FullSync(context.Background())
+	require.NoError(t, err)
+	_, err = client.Suggest(context.Background(), models.Transaction{})
+	require.Error(t, err)
+
+	require.Equal(t, []string{
+		"diff/ error NETWORK_ERROR",
+		"diff/ success ",
+		"suggest/ error RATE_LIMIT",
+	}, metrics.events)
+	require.Equal(t, int64(len(`{"serverTimestamp":1}`)), metrics.bytes)
+	require.Equal(t, []time.Duration{0}, metrics.waits)
+	require.Equal(t, []time.Duration{30 * time.Second}, metrics.limited)
+}
+
+func TestWithMetricsRecordsCircuitBreakerRejections(t *testing.T) {
+	var logs strings.Builder
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+			}, nil
+		}),
+	}
+	metrics := &recordingMetrics{}
+	client, err := api.NewClient(
+		"test-token",
+		api.WithHTTPClient(httpClient),
+		api.WithRetryPolicy(0, 0),
+		api.WithMetrics(metrics),
+		api.WithCircuitBreaker(1, time.Minute),
+		api.WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	require.NoError(t, err)
+
+	_, err = client.FullSync(context.Background())
+	require.Error(t, err)
+	_, err = client.FullSync(context.Background())
+	var apiErr *api.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, api.ErrCircuitOpen, apiErr.Code)
+
+	require.Equal(t, []string{
+		"diff/ error SERVER_ERROR",
+		"diff/ error CIRCUIT_OPEN",
+	}, metrics.events)
+	require.Equal(t, 1, strings.Count(logs.String(), "ZenMoney HTTP request started"))
+	require.Equal(t, 1, strings.Count(logs.String(), "ZenMoney HTTP request completed"))
+	require.Equal(t, 1, strings.Count(logs.String(), "ZenMoney HTTP request rejected"))
+}
+
+func TestWithMetricsMeasuresDurationsWithFixedClock(t *testing.T) {
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			time.Sleep(time.Millisecond)
+			return jsonResponse(`{"serverTimestamp":1}`), nil
+		}),
+	}
+	metrics := &recordingMetrics{}
+	client, err := api.NewClient(
+		"test-token",
+		api.WithHTTPClient(httpClient),
+		api.WithMetrics(metrics),
+		api.WithClock(fixedClock(time.Unix(1_718_454_600, 0))),
+	)
+	require.NoError(t, err)
+
+	_, err = client.FullSync(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, metrics.durations, 1)
+	require.GreaterOrEqual(t, metrics.durations[0], time.Millisecond)
+}
+
+func TestWithNilMetricsDisablesMetrics(t *testing.T) {
+	client, err := api.NewClient("test-token", api.WithMetrics(nil))
+
+	require.NoError(t, err)
+	require.NotNil(t, client)
+}
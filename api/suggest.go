@@ -19,3 +19,15 @@ func (c *Client) Suggest(ctx context.Context, transaction models.Transaction) (m
 func (c *Client) SuggestBatch(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
 	return c.internal.SuggestBatch(ctx, transactions)
 }
+
+// SuggestAll requests suggestions for any number of transactions. The input is
+// split into chunks configured with WithSuggestChunking, chunks are sent
+// concurrently, and results are returned in input order. The client timeout
+// applies to each chunk request separately.
+//
+// When some chunks fail, SuggestAll returns the partial results together with
+// a *BatchError describing the failed chunks. Positions covered by failed
+// chunks contain the unchanged input transactions.
+func (c *Client) SuggestAll(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
+	return c.internal.SuggestAll(ctx, transactions)
+}
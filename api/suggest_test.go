@@ -55,3 +55,35 @@ func TestSuggestBatchPreservesOrder(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []string{"First", "Second"}, []string{suggestions[0].Payee, suggestions[1].Payee})
 }
+
+func TestSuggestAllUsesConfiguredChunking(t *testing.T) {
+	requests := 0
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			var got []models.Transaction
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&got))
+			require.LessOrEqual(t, len(got), 2)
+
+			body, err := json.Marshal(got)
+			require.NoError(t, err)
+			return jsonResponse(string(body)), nil
+		}),
+	}
+	client, err := api.NewClient(
+		"test-token",
+		api.WithHTTPClient(httpClient),
+		api.WithSuggestChunking(2, 1),
+	)
+	require.NoError(t, err)
+
+	suggestions, err := client.SuggestAll(context.Background(), []models.Transaction{
+		{Payee: "First"},
+		{Payee: "Second"},
+		{Payee: "Third"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+	require.Equal(t, []string{"First", "Second", "Third"}, []string{suggestions[0].Payee, suggestions[1].Payee, suggestions[2].Payee})
+}
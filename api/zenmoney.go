@@ -28,6 +28,20 @@ func NewClient(token string, opts ...Option) (*Client, error) {
 		opt(cfg)
 	}
 
+	internalOptions := []client.Option{
+		client.WithSuggestChunking(cfg.suggestChunkSize, cfg.suggestConcurrency),
+		client.WithInterceptors(cfg.interceptors...),
+		client.WithOperationInterceptors(cfg.operationInterceptors...),
+		client.WithMetrics(cfg.metrics),
+		client.WithClock(cfg.clock),
+	}
+	if cfg.compressRequests {
+		internalOptions = append(internalOptions, client.WithRequestCompression(cfg.compressionThreshold))
+	}
+	if cfg.breakerEnabled {
+		internalOptions = append(internalOptions, client.WithCircuitBreaker(cfg.breakerThreshold, cfg.breakerCooldown))
+	}
+
 	internalClient, err := client.NewClient(
 		token,
 		cfg.baseURL,
@@ -37,6 +51,7 @@ func NewClient(token string, opts ...Option) (*Client, error) {
 		cfg.retryWaitTime,
 		cfg.maxResponseSize,
 		cfg.logger,
+		internalOptions...,
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,206 @@
+// Package debt tracks money lent to and borrowed from other people.
+//
+// ZenMoney records lending and borrowing as transactions against accounts of
+// type "debt", with the counterparty in Transaction.Merchant or
+// Transaction.Payee. New groups those transactions by counterparty and keeps a
+// running balance in every currency:
+//
+//	ledger := debt.New(snapshot)
+//	for _, person := range ledger.Outstanding() {
+//		for _, balance := range person.Balances {
+//			fmt.Println(person.Name, balance.Amount, balance.Instrument)
+//		}
+//	}
+//
+// Positive amounts are owed to the user; negative amounts are owed by the user.
+package debt
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Entry is one debt transaction of a counterparty.
+type Entry struct {
+	Transaction models.Transaction
+
+	// Instrument is the currency of the debt side of the transaction.
+	Instrument int
+
+	// Amount is the change of the counterparty's balance: positive when the
+	// user lends money or repays a debt, negative when the user borrows money
+	// or receives a repayment.
+	Amount float64
+}
+
+// Balance is the outstanding amount of a counterparty in one currency.
+type Balance struct {
+	Instrument int
+	Amount     float64
+}
+
+// Counterparty is a person or organization the user lends to or borrows from.
+type Counterparty struct {
+	// Key identifies the counterparty: the merchant ID when transactions
+	// reference a merchant, and the normalized payee otherwise. It is empty
+	// for debt transactions without a counterparty.
+	Key string
+
+	// Name is the merchant title or the payee of the earliest entry.
+	Name string
+
+	// Merchant is the merchant ID, or empty for payee-only counterparties.
+	Merchant string
+
+	// Balances lists the non-zero outstanding amounts by instrument.
+	Balances []Balance
+
+	// Entries lists the debt transactions by date.
+	Entries []Entry
+}
+
+// Ledger groups debt transactions by counterparty.
+type Ledger struct {
+	// Counterparties is sorted by name.
+	Counterparties []*Counterparty
+}
+
+// New builds a ledger from the accounts, merchants, and transactions of
+// snapshot. Deleted transactions and transactions that do not touch a debt
+// account are ignored.
+func New(snapshot models.Response) *Ledger {
+	debtAccounts := make(map[string]bool)
+	for _, account := range snapshot.Account {
+		if account.AccountType() == models.AccountTypeDebt {
+			debtAccounts[account.ID] = true
+		}
+	}
+	merchants := make(map[string]string, len(snapshot.Merchant))
+	for _, merchant := range snapshot.Merchant {
+		merchants[merchant.ID] = merchant.Title
+	}
+
+	byKey := make(map[string]*Counterparty)
+	for _, transaction := range snapshot.Transaction {
+		if transaction.Deleted {
+			continue
+		}
+		entry, ok := entryOf(transaction, debtAccounts)
+		if !ok {
+			continue
+		}
+
+		key, merchant := counterpartyOf(transaction)
+		counterparty := byKey[key]
+		if counterparty == nil {
+			counterparty = &Counterparty{Key: key, Name: merchants[merchant], Merchant: merchant}
+			byKey[key] = counterparty
+		}
+		counterparty.Entries = append(counterparty.Entries, entry)
+	}
+
+	ledger := &Ledger{}
+	for _, counterparty := range byKey {
+		slices.SortStableFunc(counterparty.Entries, func(a, b Entry) int {
+			return cmp.Or(
+				cmp.Compare(a.Transaction.Date, b.Transaction.Date),
+				cmp.Compare(a.Transaction.Created, b.Transaction.Created),
+			)
+		})
+		if counterparty.Name == "" {
+			counterparty.Name = strings.TrimSpace(counterparty.Entries[0].Transaction.Payee)
+		}
+		counterparty.Balances = balances(counterparty.Entries)
+		ledger.Counterparties = append(ledger.Counterparties, counterparty)
+	}
+	slices.SortFunc(ledger.Counterparties, func(a, b *Counterparty) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Key, b.Key))
+	})
+
+	return ledger
+}
+
+// Counterparty returns the counterparty with key, or nil.
+func (l *Ledger) Counterparty(key string) *Counterparty {
+	for _, counterparty := range l.Counterparties {
+		if counterparty.Key == key {
+			return counterparty
+		}
+	}
+
+	return nil
+}
+
+// Outstanding returns the counterparties with a non-zero balance in any
+// currency.
+func (l *Ledger) Outstanding() []*Counterparty {
+	var result []*Counterparty
+	for _, counterparty := range l.Counterparties {
+		if len(counterparty.Balances) > 0 {
+			result = append(result, counterparty)
+		}
+	}
+
+	return result
+}
+
+// Totals returns the sum of all outstanding balances by instrument: positive
+// amounts are owed to the user and negative amounts by the user.
+func (l *Ledger) Totals() []Balance {
+	var entries []Entry
+	for _, counterparty := range l.Counterparties {
+		entries = append(entries, counterparty.Entries...)
+	}
+
+	return balances(entries)
+}
+
+// entryOf converts a transaction into a debt entry. Money moved into a debt
+// account increases what the counterparty owes; money moved out of it
+// decreases it.
+func entryOf(transaction models.Transaction, debtAccounts map[string]bool) (Entry, bool) {
+	income := debtAccounts[transaction.IncomeAccount]
+	outcome := transaction.OutcomeAccount != nil && debtAccounts[*transaction.OutcomeAccount]
+	switch {
+	case income && !outcome:
+		return Entry{Transaction: transaction, Instrument: transaction.IncomeInstrument, Amount: transaction.Income}, true
+	case outcome && !income:
+		return Entry{Transaction: transaction, Instrument: transaction.OutcomeInstrument, Amount: -transaction.Outcome}, true
+	default:
+		return Entry{}, false
+	}
+}
+
+// counterpartyOf returns the counterparty key and merchant ID of a
+// transaction.
+func counterpartyOf(transaction models.Transaction) (key string, merchant string) {
+	if transaction.Merchant != nil && *transaction.Merchant != "" {
+		return *transaction.Merchant, *transaction.Merchant
+	}
+
+	return finance.NormalizePayee(transaction.Payee), ""
+}
+
+// balances sums entries by instrument and drops currencies that are settled.
+func balances(entries []Entry) []Balance {
+	sums := make(map[int]float64)
+	for _, entry := range entries {
+		sums[entry.Instrument] += entry.Amount
+	}
+
+	var result []Balance
+	for instrument, amount := range sums {
+		if amount = finance.Round(amount); amount != 0 {
+			result = append(result, Balance{Instrument: instrument, Amount: amount})
+		}
+	}
+	slices.SortFunc(result, func(a, b Balance) int {
+		return cmp.Compare(a.Instrument, b.Instrument)
+	})
+
+	return result
+}
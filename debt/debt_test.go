@@ -0,0 +1,88 @@
+package debt_test
+
+import (
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/debt"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rub = 1
+	usd = 2
+)
+
+func transfer(id string, date string, from string, to string, amount float64, instrument int, payee string) models.Transaction {
+	return models.Transaction{
+		ID:                id,
+		Date:              date,
+		IncomeAccount:     to,
+		OutcomeAccount:    &from,
+		Income:            amount,
+		Outcome:           amount,
+		IncomeInstrument:  instrument,
+		OutcomeInstrument: instrument,
+		Payee:             payee,
+	}
+}
+
+func TestLedger(t *testing.T) {
+	shop := "merchant-shop"
+	fromShop := transfer("t5", "2024-01-07", "debt", "card", 300, rub, "")
+	fromShop.Merchant = &shop
+	deleted := transfer("t6", "2024-01-08", "card", "debt", 999, rub, "Anna")
+	deleted.Deleted = true
+
+	snapshot := models.Response{
+		Account: []models.Account{
+			{ID: "card", Type: "ccard"},
+			{ID: "debt", Type: "debt"},
+		},
+		Merchant: []models.Merchant{{ID: shop, Title: "Corner Shop"}},
+		Transaction: []models.Transaction{
+			transfer("t2", "2024-01-05", "debt", "card", 400, rub, "anna "),
+			transfer("t1", "2024-01-01", "card", "debt", 1000, rub, "Anna"),
+			transfer("t3", "2024-01-02", "card", "debt", 50, usd, "Anna"),
+			transfer("t4", "2024-01-03", "debt", "card", 200, rub, "Boris"),
+			transfer("t7", "2024-01-04", "card", "debt", 200, rub, "Boris"),
+			transfer("t8", "2024-01-04", "card", "cash", 10, rub, "Boris"),
+			fromShop,
+			deleted,
+		},
+	}
+
+	ledger := debt.New(snapshot)
+
+	require.Len(t, ledger.Counterparties, 3)
+	anna := ledger.Counterparty("anna")
+	require.NotNil(t, anna)
+	require.Equal(t, "Anna", anna.Name)
+	require.Equal(t, []debt.Balance{{Instrument: rub, Amount: 600}, {Instrument: usd, Amount: 50}}, anna.Balances)
+	require.Equal(t, []string{"t1", "t3", "t2"}, ids(anna.Entries))
+	require.Equal(t, -400.0, anna.Entries[2].Amount)
+
+	boris := ledger.Counterparty("boris")
+	require.Empty(t, boris.Balances)
+	require.Len(t, boris.Entries, 2)
+
+	shopDebt := ledger.Counterparty(shop)
+	require.Equal(t, "Corner Shop", shopDebt.Name)
+	require.Equal(t, shop, shopDebt.Merchant)
+	require.Equal(t, []debt.Balance{{Instrument: rub, Amount: -300}}, shopDebt.Balances)
+
+	outstanding := ledger.Outstanding()
+	require.Len(t, outstanding, 2)
+	require.Equal(t, "Anna", outstanding[0].Name)
+	require.Equal(t, "Corner Shop", outstanding[1].Name)
+	require.Equal(t, []debt.Balance{{Instrument: rub, Amount: 300}, {Instrument: usd, Amount: 50}}, ledger.Totals())
+}
+
+func ids(entries []debt.Entry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Transaction.ID
+	}
+
+	return result
+}
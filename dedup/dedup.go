@@ -0,0 +1,291 @@
+// Package dedup detects likely duplicate transactions before they are uploaded.
+//
+// Importers that combine several sources often produce the same purchase more
+// than once. Find compares candidate transactions with transactions that
+// already exist in ZenMoney and returns scored matches; Filter uses those
+// matches to drop duplicates before the candidates are sent with Client.Sync.
+package dedup
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Reason explains why a candidate was matched to an existing transaction.
+type Reason string
+
+const (
+	// ReasonSameID means both transactions share a primary ID.
+	ReasonSameID Reason = "same_id"
+	// ReasonBankID means both transactions carry the same bank operation ID.
+	ReasonBankID Reason = "bank_id"
+	// ReasonAccount means both transactions move money through the same account.
+	ReasonAccount Reason = "account"
+	// ReasonAmount means both transactions have matching amounts.
+	ReasonAmount Reason = "amount"
+	// ReasonDate means both transactions fall within the configured date window.
+	ReasonDate Reason = "date"
+	// ReasonPayee means the original payees are similar.
+	ReasonPayee Reason = "payee"
+)
+
+// minPayeeSimilarity is the PayeeSimilarity from which two payees are
+// considered the same.
+const minPayeeSimilarity = 0.5
+
+// Options controls duplicate detection. Zero values select the defaults.
+type Options struct {
+	// DateWindow is the maximum distance in days between duplicate
+	// transactions. It defaults to 2 days to cover posting delays.
+	DateWindow int
+
+	// AmountTolerance is the maximum absolute amount difference. It defaults
+	// to 0.01.
+	AmountTolerance float64
+
+	// MinScore is the lowest score reported as a duplicate, between 0 and 1.
+	// It defaults to 0.6.
+	MinScore float64
+}
+
+// Match describes a candidate that is likely a duplicate of an existing
+// transaction.
+type Match struct {
+	// CandidateIndex is the position of Candidate in the input slice.
+	CandidateIndex int
+	Candidate      models.Transaction
+	Existing       models.Transaction
+
+	// Score is the confidence of the match, from 0 to 1.
+	Score float64
+
+	// Reasons lists the signals that contributed to Score.
+	Reasons []Reason
+}
+
+func (o Options) withDefaults() Options {
+	if o.DateWindow <= 0 {
+		o.DateWindow = 2
+	}
+	if o.AmountTolerance <= 0 {
+		o.AmountTolerance = 0.01
+	}
+	if o.MinScore <= 0 {
+		o.MinScore = 0.6
+	}
+
+	return o
+}
+
+// Find returns the best existing match for each candidate whose score reaches
+// opts.MinScore. Every existing transaction is matched at most once, so two
+// identical purchases on the same day are not both reported against a single
+// stored transaction. Transactions whose payees are both set but dissimilar are
+// never matched unless they share an ID or a bank operation ID, and
+// transactions that both carry a bank operation ID for the same side are never
+// matched when the IDs differ. Deleted existing transactions are ignored.
+// Matches are ordered by CandidateIndex.
+func Find(candidates []models.Transaction, existing []models.Transaction, opts Options) []Match {
+	opts = opts.withDefaults()
+
+	// pairs remember the position of the existing transaction, so stored
+	// transactions without an ID are matched independently.
+	type pair struct {
+		match    Match
+		existing int
+	}
+	var pairs []pair
+	for i, candidate := range candidates {
+		for j, stored := range existing {
+			if stored.Deleted {
+				continue
+			}
+			if match, ok := compare(candidate, stored, opts); ok {
+				match.CandidateIndex = i
+				pairs = append(pairs, pair{match: match, existing: j})
+			}
+		}
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		return cmp.Or(
+			cmp.Compare(b.match.Score, a.match.Score),
+			cmp.Compare(a.match.CandidateIndex, b.match.CandidateIndex),
+		)
+	})
+
+	matchedCandidates := make(map[int]bool)
+	matchedExisting := make(map[int]bool)
+	var result []Match
+	for _, p := range pairs {
+		if matchedCandidates[p.match.CandidateIndex] || matchedExisting[p.existing] {
+			continue
+		}
+		matchedCandidates[p.match.CandidateIndex] = true
+		matchedExisting[p.existing] = true
+		result = append(result, p.match)
+	}
+	slices.SortFunc(result, func(a, b Match) int {
+		return cmp.Compare(a.CandidateIndex, b.CandidateIndex)
+	})
+
+	return result
+}
+
+// Filter returns the candidates that are not duplicates of existing
+// transactions or of earlier candidates, together with the matches that caused
+// the remaining candidates to be dropped. Like Find, it matches every existing
+// transaction and every kept candidate at most once.
+func Filter(candidates []models.Transaction, existing []models.Transaction, opts Options) ([]models.Transaction, []Match) {
+	opts = opts.withDefaults()
+
+	pool := slices.Clone(existing)
+	var unique []models.Transaction
+	var duplicates []Match
+	for i, candidate := range candidates {
+		if match, j, ok := bestMatch(candidate, pool, opts); ok {
+			match.CandidateIndex = i
+			duplicates = append(duplicates, match)
+			pool = slices.Delete(pool, j, j+1)
+			continue
+		}
+		unique = append(unique, candidate)
+		pool = append(pool, candidate)
+	}
+
+	return unique, duplicates
+}
+
+// bestMatch returns the highest scoring match of candidate in pool and the
+// position of the matched transaction. Ties go to the earlier transaction.
+func bestMatch(candidate models.Transaction, pool []models.Transaction, opts Options) (Match, int, bool) {
+	var best Match
+	index := -1
+	for j, stored := range pool {
+		if stored.Deleted {
+			continue
+		}
+		if match, ok := compare(candidate, stored, opts); ok && (index < 0 || match.Score > best.Score) {
+			best = match
+			index = j
+		}
+	}
+
+	return best, index, index >= 0
+}
+
+func compare(candidate models.Transaction, stored models.Transaction, opts Options) (Match, bool) {
+	match := Match{Candidate: candidate, Existing: stored}
+	if candidate.ID != "" && candidate.ID == stored.ID {
+		match.Score = 1
+		match.Reasons = []Reason{ReasonSameID}
+		return match, true
+	}
+	if !sharesAccount(candidate, stored) {
+		return Match{}, false
+	}
+	match.Reasons = append(match.Reasons, ReasonAccount)
+
+	// Different bank operation IDs identify different operations, whatever the
+	// other fields say.
+	if differentBankID(candidate.IncomeBankID, stored.IncomeBankID) || differentBankID(candidate.OutcomeBankID, stored.OutcomeBankID) {
+		return Match{}, false
+	}
+	if sameBankID(candidate.IncomeBankID, stored.IncomeBankID) || sameBankID(candidate.OutcomeBankID, stored.OutcomeBankID) {
+		match.Score = 1
+		match.Reasons = append(match.Reasons, ReasonBankID)
+		return match, true
+	}
+
+	if math.Abs(candidate.Income-stored.Income) > opts.AmountTolerance ||
+		math.Abs(candidate.Outcome-stored.Outcome) > opts.AmountTolerance {
+		return Match{}, false
+	}
+	match.Reasons = append(match.Reasons, ReasonAmount)
+
+	days, ok := dayDistance(candidate.Date, stored.Date)
+	if !ok || days > opts.DateWindow {
+		return Match{}, false
+	}
+	match.Reasons = append(match.Reasons, ReasonDate)
+
+	// Amount and account agreement alone is a moderate signal; date proximity
+	// and payee similarity decide whether it is strong enough. Two known but
+	// different payees are separate purchases that happen to cost the same.
+	candidatePayee, storedPayee := payeeOf(candidate), payeeOf(stored)
+	payee := PayeeSimilarity(candidatePayee, storedPayee)
+	switch {
+	case payee >= minPayeeSimilarity:
+		match.Reasons = append(match.Reasons, ReasonPayee)
+	case finance.NormalizePayee(candidatePayee) != "" && finance.NormalizePayee(storedPayee) != "":
+		return Match{}, false
+	}
+	dateScore := 1 - float64(days)/float64(opts.DateWindow+1)
+	match.Score = 0.4 + 0.25*dateScore + 0.35*payee
+	if match.Score < opts.MinScore {
+		return Match{}, false
+	}
+
+	return match, true
+}
+
+func sharesAccount(a models.Transaction, b models.Transaction) bool {
+	aAccounts := accountsOf(a)
+	for _, account := range accountsOf(b) {
+		if slices.Contains(aAccounts, account) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func accountsOf(transaction models.Transaction) []string {
+	result := make([]string, 0, 2)
+	if transaction.IncomeAccount != "" {
+		result = append(result, transaction.IncomeAccount)
+	}
+	if transaction.OutcomeAccount != nil && *transaction.OutcomeAccount != "" {
+		result = append(result, *transaction.OutcomeAccount)
+	}
+
+	return result
+}
+
+func sameBankID(a *string, b *string) bool {
+	return a != nil && b != nil && *a != "" && *a == *b
+}
+
+func differentBankID(a *string, b *string) bool {
+	return a != nil && b != nil && *a != "" && *b != "" && *a != *b
+}
+
+func payeeOf(transaction models.Transaction) string {
+	if transaction.OriginalPayee != "" {
+		return transaction.OriginalPayee
+	}
+
+	return transaction.Payee
+}
+
+func dayDistance(a string, b string) (int, bool) {
+	first, err := time.Parse(time.DateOnly, a)
+	if err != nil {
+		return 0, false
+	}
+	second, err := time.Parse(time.DateOnly, b)
+	if err != nil {
+		return 0, false
+	}
+
+	days := int(first.Sub(second).Hours() / 24)
+	if days < 0 {
+		days = -days
+	}
+
+	return days, true
+}
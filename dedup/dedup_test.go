@@ -0,0 +1,177 @@
+package dedup_test
+
+import (
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/dedup"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/txtest"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	bankID := "op-42"
+	withBankID := txtest.Expense("new-bank", "2024-05-10", 99, txtest.OriginalPayee("Something else"))
+	withBankID.OutcomeBankID = &bankID
+	storedWithBankID := txtest.Expense("old-bank", "2024-05-01", 12, txtest.OriginalPayee("Shop"))
+	storedWithBankID.OutcomeBankID = &bankID
+
+	existing := []models.Transaction{
+		txtest.Expense("old-coffee", "2024-05-02", 250, txtest.OriginalPayee("STARBUCKS 1234 MOSCOW")),
+		txtest.Expense("old-taxi", "2024-05-02", 400, txtest.OriginalPayee("Yandex Go")),
+		storedWithBankID,
+		{ID: "old-deleted", Date: "2024-05-03", IncomeAccount: "card", Outcome: 70, Deleted: true},
+	}
+	candidates := []models.Transaction{
+		txtest.Expense("new-coffee", "2024-05-03", 250, txtest.OriginalPayee("Starbucks")),
+		txtest.Expense("new-taxi", "2024-05-02", 401, txtest.OriginalPayee("Yandex Go")),
+		withBankID,
+		txtest.Expense("new-deleted", "2024-05-03", 70),
+		txtest.Expense("old-taxi", "2024-06-01", 1),
+	}
+
+	matches := dedup.Find(candidates, existing, dedup.Options{})
+
+	require.Len(t, matches, 3)
+	require.Equal(t, 0, matches[0].CandidateIndex)
+	require.Equal(t, "old-coffee", matches[0].Existing.ID)
+	require.Contains(t, matches[0].Reasons, dedup.ReasonPayee)
+	require.Greater(t, matches[0].Score, 0.8)
+
+	require.Equal(t, 2, matches[1].CandidateIndex)
+	require.Equal(t, "old-bank", matches[1].Existing.ID)
+	require.Equal(t, 1.0, matches[1].Score)
+	require.Contains(t, matches[1].Reasons, dedup.ReasonBankID)
+
+	require.Equal(t, 4, matches[2].CandidateIndex)
+	require.Equal(t, []dedup.Reason{dedup.ReasonSameID}, matches[2].Reasons)
+}
+
+func TestFindMatchesEachExistingTransactionOnce(t *testing.T) {
+	existing := []models.Transaction{txtest.Expense("old", "2024-05-02", 100, txtest.OriginalPayee("Bakery"))}
+	candidates := []models.Transaction{
+		txtest.Expense("a", "2024-05-03", 100, txtest.OriginalPayee("Bakery")),
+		txtest.Expense("b", "2024-05-02", 100, txtest.OriginalPayee("Bakery")),
+	}
+
+	matches := dedup.Find(candidates, existing, dedup.Options{})
+
+	require.Len(t, matches, 1)
+	require.Equal(t, 1, matches[0].CandidateIndex)
+}
+
+func TestFindMatchesExistingTransactionsWithoutID(t *testing.T) {
+	existing := []models.Transaction{
+		txtest.Expense("", "2024-05-02", 100, txtest.OriginalPayee("Bakery")),
+		txtest.Expense("", "2024-05-02", 100, txtest.OriginalPayee("Bakery")),
+	}
+	candidates := []models.Transaction{
+		txtest.Expense("a", "2024-05-02", 100, txtest.OriginalPayee("Bakery")),
+		txtest.Expense("b", "2024-05-02", 100, txtest.OriginalPayee("Bakery")),
+	}
+
+	require.Len(t, dedup.Find(candidates, existing, dedup.Options{}), 2)
+}
+
+func TestFindRespectsOptions(t *testing.T) {
+	existing := []models.Transaction{txtest.Expense("old", "2024-05-01", 100, txtest.OriginalPayee("Bakery"))}
+	candidates := []models.Transaction{txtest.Expense("new", "2024-05-05", 100.5, txtest.OriginalPayee("Bakery"))}
+
+	require.Empty(t, dedup.Find(candidates, existing, dedup.Options{}))
+	require.Len(t, dedup.Find(candidates, existing, dedup.Options{
+		DateWindow:      5,
+		AmountTolerance: 1,
+	}), 1)
+}
+
+func TestFindRequiresSharedAccount(t *testing.T) {
+	other := txtest.Expense("old", "2024-05-01", 100, txtest.FromAccount("wallet"), txtest.OriginalPayee("Bakery"))
+
+	matches := dedup.Find([]models.Transaction{txtest.Expense("new", "2024-05-01", 100, txtest.OriginalPayee("Bakery"))}, []models.Transaction{other}, dedup.Options{})
+
+	require.Empty(t, matches)
+}
+
+func TestFindKeepsSameAmountAtDifferentPayees(t *testing.T) {
+	existing := []models.Transaction{txtest.Expense("old", "2024-05-01", 150, txtest.OriginalPayee("Coffee Bean"))}
+	candidates := []models.Transaction{
+		txtest.Expense("other-shop", "2024-05-01", 150, txtest.OriginalPayee("Surf Coffee")),
+		txtest.Expense("no-payee", "2024-05-01", 150),
+	}
+
+	matches := dedup.Find(candidates, existing, dedup.Options{})
+
+	require.Len(t, matches, 1)
+	require.Equal(t, 1, matches[0].CandidateIndex)
+
+	unique, duplicates := dedup.Filter(candidates[:1], existing, dedup.Options{})
+	require.Len(t, unique, 1)
+	require.Empty(t, duplicates)
+}
+
+func TestFindKeepsDifferentBankIDs(t *testing.T) {
+	storedID, candidateID := "op-1", "op-2"
+	stored := txtest.Expense("old", "2024-05-01", 100, txtest.OriginalPayee("Bakery"))
+	stored.OutcomeBankID = &storedID
+	candidate := txtest.Expense("new", "2024-05-01", 100, txtest.OriginalPayee("Bakery"))
+	candidate.OutcomeBankID = &candidateID
+
+	require.Empty(t, dedup.Find([]models.Transaction{candidate}, []models.Transaction{stored}, dedup.Options{}))
+}
+
+func TestFilter(t *testing.T) {
+	existing := []models.Transaction{txtest.Expense("old", "2024-05-01", 100, txtest.OriginalPayee("Bakery"))}
+	candidates := []models.Transaction{
+		txtest.Expense("a", "2024-05-01", 100, txtest.OriginalPayee("BAKERY #12")),
+		txtest.Expense("b", "2024-05-04", 30, txtest.OriginalPayee("Kiosk")),
+		txtest.Expense("c", "2024-05-04", 30, txtest.OriginalPayee("Kiosk")),
+	}
+
+	unique, duplicates := dedup.Filter(candidates, existing, dedup.Options{})
+
+	require.Len(t, unique, 1)
+	require.Equal(t, "b", unique[0].ID)
+	require.Len(t, duplicates, 2)
+	require.Equal(t, 0, duplicates[0].CandidateIndex)
+	require.Equal(t, "old", duplicates[0].Existing.ID)
+	require.Equal(t, 2, duplicates[1].CandidateIndex)
+	require.Equal(t, "b", duplicates[1].Existing.ID)
+}
+
+func TestPayeeSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		min  float64
+		max  float64
+	}{
+		{a: "Coffee-House", b: "coffee house", min: 1, max: 1},
+		{a: "STARBUCKS", b: "Starbucks 1234 Moscow", min: 0.8, max: 0.8},
+		{a: "Pyaterochka", b: "Pyaterochka 6011", min: 0.8, max: 1},
+		{a: "Lenta", b: "Auchan", min: 0, max: 0.3},
+		{a: "", b: "", min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			score := dedup.PayeeSimilarity(tt.a, tt.b)
+			require.GreaterOrEqual(t, score, tt.min)
+			require.LessOrEqual(t, score, tt.max)
+		})
+	}
+}
+
+func TestFilterMatchesEachExistingTransactionOnce(t *testing.T) {
+	existing := []models.Transaction{txtest.Expense("old", "2024-05-01", 100, txtest.OriginalPayee("Bakery"))}
+	candidates := []models.Transaction{
+		txtest.Expense("a", "2024-05-01", 100, txtest.OriginalPayee("Bakery")),
+		txtest.Expense("b", "2024-05-01", 100, txtest.OriginalPayee("Bakery")),
+	}
+
+	unique, duplicates := dedup.Filter(candidates, existing, dedup.Options{})
+
+	require.Len(t, unique, 1)
+	require.Equal(t, "b", unique[0].ID)
+	require.Len(t, duplicates, 1)
+	require.Equal(t, 0, duplicates[0].CandidateIndex)
+	require.Equal(t, "old", duplicates[0].Existing.ID)
+}
@@ -0,0 +1,61 @@
+package dedup
+
+import (
+	"strings"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+)
+
+// NormalizePayee lowercases payee and reduces it to letters and digits
+// separated by single spaces, so bank-specific punctuation and spacing do not
+// affect comparisons. It is the normalization used by Find and
+// PayeeSimilarity.
+func NormalizePayee(payee string) string {
+	return finance.NormalizePayee(payee)
+}
+
+// PayeeSimilarity returns a similarity score from 0 to 1 for two payee names.
+// Both names are normalized first. One name containing the other, such as
+// "STARBUCKS" and "STARBUCKS 1234 MOSCOW", scores at least 0.8. Two empty
+// payees are considered unrelated and score 0.
+func PayeeSimilarity(a string, b string) float64 {
+	a = finance.NormalizePayee(a)
+	b = finance.NormalizePayee(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	first := []rune(a)
+	second := []rune(b)
+	longest := max(len(first), len(second))
+	score := 1 - float64(levenshtein(first, second))/float64(longest)
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		score = max(score, 0.8)
+	}
+
+	return score
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
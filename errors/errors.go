@@ -1,7 +1,11 @@
 // Package errors defines errors returned by the ZenMoney SDK.
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
 
 // ErrorCode identifies a category of SDK error.
 type ErrorCode string
@@ -13,6 +17,8 @@ const (
 	ErrNetworkError     ErrorCode = "NETWORK_ERROR"
 	ErrRateLimit        ErrorCode = "RATE_LIMIT"
 	ErrResponseTooLarge ErrorCode = "RESPONSE_TOO_LARGE"
+	ErrStorage          ErrorCode = "STORAGE_ERROR"
+	ErrCircuitOpen      ErrorCode = "CIRCUIT_OPEN"
 )
 
 // Error describes an error returned by the SDK.
@@ -51,3 +57,33 @@ func New(code ErrorCode, message string, err error) *Error {
 		Err:     err,
 	}
 }
+
+// BatchError reports the chunks of a batch operation that failed. Results for
+// the remaining chunks are still returned by the operation.
+type BatchError struct {
+	// ChunkSize is the number of items in each chunk. Chunk i covers input
+	// items from i*ChunkSize up to, but not including, (i+1)*ChunkSize.
+	ChunkSize int
+	// Chunks maps the index of each failed chunk to its error.
+	Chunks map[int]error
+}
+
+func (e *BatchError) Error() string {
+	indexes := slices.Sorted(maps.Keys(e.Chunks))
+	if len(indexes) == 0 {
+		return "batch failed"
+	}
+
+	return fmt.Sprintf("%d batch chunks failed, first at chunk %d: %v", len(indexes), indexes[0], e.Chunks[indexes[0]])
+}
+
+// Unwrap returns the chunk errors ordered by chunk index.
+func (e *BatchError) Unwrap() []error {
+	indexes := slices.Sorted(maps.Keys(e.Chunks))
+	result := make([]error, 0, len(indexes))
+	for _, index := range indexes {
+		result = append(result, e.Chunks[index])
+	}
+
+	return result
+}
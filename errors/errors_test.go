@@ -25,3 +25,36 @@ func TestNew(t *testing.T) {
 		require.Equal(t, "NETWORK_ERROR: network issue occurred: root error", err.Error())
 	})
 }
+
+func TestBatchError(t *testing.T) {
+	first := sdkerrors.New(sdkerrors.ErrServerError, "server failed", nil)
+	second := sdkerrors.New(sdkerrors.ErrRateLimit, "too many requests", nil)
+	err := &sdkerrors.BatchError{
+		ChunkSize: 10,
+		Chunks:    map[int]error{3: second, 1: first},
+	}
+
+	require.Equal(t, "2 batch chunks failed, first at chunk 1: SERVER_ERROR: server failed", err.Error())
+	require.Equal(t, []error{first, second}, err.Unwrap())
+	require.ErrorIs(t, err, second)
+
+	var sdkErr *sdkerrors.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, sdkerrors.ErrServerError, sdkErr.Code)
+}
+
+func TestValidationError(t *testing.T) {
+	err := &sdkerrors.ValidationError{
+		Entity: "transaction",
+		Fields: []*sdkerrors.FieldError{
+			{Field: "id", Message: "must be a UUID"},
+			{Field: "tag[1]", Message: "is required"},
+		},
+	}
+
+	require.Equal(t, "invalid transaction: id: must be a UUID; tag[1]: is required", err.Error())
+
+	var fieldErr *sdkerrors.FieldError
+	require.True(t, stdErrors.As(err, &fieldErr))
+	require.Equal(t, "id", fieldErr.Field)
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"strings"
+)
+
+// FieldError describes one invalid field. Field is a path of JSON field names
+// such as "tag[1]".
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists the invalid fields of an entity. Validate methods
+// return it as the cause of an *Error with ErrInvalidRequest, before any
+// request is sent, so the server never sees the invalid value.
+type ValidationError struct {
+	// Entity is the entity type, for example "transaction".
+	Entity string
+	// Fields lists the problems in field order.
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+
+	return "invalid " + e.Entity + ": " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the field errors.
+func (e *ValidationError) Unwrap() []error {
+	result := make([]error, len(e.Fields))
+	for i, field := range e.Fields {
+		result[i] = field
+	}
+
+	return result
+}
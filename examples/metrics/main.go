@@ -0,0 +1,166 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/api"
+)
+
+// latencyBuckets are the upper bounds of the attempt latency histogram in
+// seconds. They match the default buckets of the Prometheus Go client.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// requestLabels are the labels of the request histogram.
+type requestLabels struct {
+	endpoint string
+	outcome  string
+	code     api.ErrorCode
+}
+
+type histogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// prometheusMetrics adapts api.Metrics to the Prometheus text format served at
+// /metrics without extra dependencies. With the Prometheus Go client, each map
+// becomes a vector with the same labels: requests a HistogramVec labeled with
+// endpoint, outcome, and code, and the others CounterVecs labeled with
+// endpoint.
+type prometheusMetrics struct {
+	mu               sync.Mutex
+	requests         map[requestLabels]*histogram
+	bytes            map[string]int64
+	retries          map[string]int64
+	rateLimited      map[string]int64
+	rateLimitSeconds map[string]float64
+}
+
+func newPrometheusMetrics() *prometheusMetrics {
+	return &prometheusMetrics{
+		requests:         make(map[requestLabels]*histogram),
+		bytes:            make(map[string]int64),
+		retries:          make(map[string]int64),
+		rateLimited:      make(map[string]int64),
+		rateLimitSeconds: make(map[string]float64),
+	}
+}
+
+func (m *prometheusMetrics) RequestCompleted(endpoint string, outcome string, code api.ErrorCode, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	labels := requestLabels{endpoint: endpoint, outcome: outcome, code: code}
+	h, ok := m.requests[labels]
+	if !ok {
+		h = &histogram{buckets: make([]uint64, len(latencyBuckets))}
+		m.requests[labels] = h
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (m *prometheusMetrics) BytesReceived(endpoint string, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes[endpoint] += bytes
+}
+
+func (m *prometheusMetrics) RetryScheduled(endpoint string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[endpoint]++
+}
+
+func (m *prometheusMetrics) RateLimited(endpoint string, retryAfter time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rateLimited[endpoint]++
+	m.rateLimitSeconds[endpoint] += retryAfter.Seconds()
+}
+
+// ServeHTTP writes the collected metrics in the Prometheus text format.
+func (m *prometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP zenmoney_request_duration_seconds Latency of HTTP attempts to the ZenMoney API.")
+	fmt.Fprintln(w, "# TYPE zenmoney_request_duration_seconds histogram")
+	keys := make([]requestLabels, 0, len(m.requests))
+	for labels := range m.requests {
+		keys = append(keys, labels)
+	}
+	slices.SortFunc(keys, func(a, b requestLabels) int {
+		return cmp.Or(cmp.Compare(a.endpoint, b.endpoint), cmp.Compare(a.outcome, b.outcome), cmp.Compare(a.code, b.code))
+	})
+	for _, labels := range keys {
+		h := m.requests[labels]
+		base := fmt.Sprintf("endpoint=%q,outcome=%q,code=%q", labels.endpoint, labels.outcome, labels.code)
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "zenmoney_request_duration_seconds_bucket{%s,le=%q} %d\n", base, strconv.FormatFloat(bound, 'g', -1, 64), h.buckets[i])
+		}
+		fmt.Fprintf(w, "zenmoney_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", base, h.count)
+		fmt.Fprintf(w, "zenmoney_request_duration_seconds_sum{%s} %g\n", base, h.sum)
+		fmt.Fprintf(w, "zenmoney_request_duration_seconds_count{%s} %d\n", base, h.count)
+	}
+
+	writeCounter(w, "zenmoney_received_bytes_total", "Response bytes received on the wire.", m.bytes)
+	writeCounter(w, "zenmoney_retries_total", "Retries scheduled after failed attempts.", m.retries)
+	writeCounter(w, "zenmoney_rate_limited_total", "Responses with 429 Too Many Requests.", m.rateLimited)
+	writeCounter(w, "zenmoney_rate_limit_wait_seconds_total", "Delay requested by Retry-After headers.", m.rateLimitSeconds)
+}
+
+// writeCounter writes a counter labeled with the endpoint.
+func writeCounter[V int64 | float64](w io.Writer, name string, help string, values map[string]V) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	endpoints := make([]string, 0, len(values))
+	for endpoint := range values {
+		endpoints = append(endpoints, endpoint)
+	}
+	slices.Sort(endpoints)
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(w, "%s{endpoint=%q} %v\n", name, endpoint, values[endpoint])
+	}
+}
+
+func main() {
+	metrics := newPrometheusMetrics()
+	client, err := api.NewClient(
+		"your-token-here",
+		api.WithMetrics(metrics),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	http.Handle("/metrics", metrics)
+	go func() {
+		log.Print(http.ListenAndServe("localhost:8080", nil))
+	}()
+
+	if _, err := client.FullSync(context.Background()); err != nil {
+		log.Printf("Full sync failed: %v", err)
+	}
+
+	log.Print("Metrics are available at http://localhost:8080/metrics")
+	select {}
+}
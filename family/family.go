@@ -0,0 +1,181 @@
+// Package family supports ZenMoney family accounting, where several users
+// share one budget.
+//
+// The users of a family are returned together in Response.User: the head of
+// the family has no User.Parent, and every other member refers to the head.
+// Accounts belong to the member in Account.Role, or to Account.User when no
+// role is set, and private accounts are visible only to their member.
+//
+//	f := family.New(snapshot)
+//	for _, member := range f.Members {
+//		view := f.View(snapshot, member.User.ID)
+//		fmt.Println(member.User.Login, len(view.Transaction))
+//	}
+package family
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Member is a user of a family.
+type Member struct {
+	User models.User
+
+	// Parent is the head of the family, or nil for the head.
+	Parent *Member
+
+	// Children are the members that refer to this member, sorted by ID.
+	Children []*Member
+}
+
+// Family is the tree of users in a snapshot.
+type Family struct {
+	// Head is the member without a parent. When a snapshot contains several
+	// such users, Head is the one with the lowest ID.
+	Head *Member
+
+	// Members lists all users sorted by ID.
+	Members []*Member
+
+	accounts map[string]models.Account
+}
+
+// New builds the family tree from the users of snapshot and indexes its
+// accounts. Members whose parent is not in the snapshot are treated as heads.
+func New(snapshot models.Response) *Family {
+	f := &Family{accounts: make(map[string]models.Account, len(snapshot.Account))}
+	byID := make(map[int]*Member, len(snapshot.User))
+	for _, user := range snapshot.User {
+		member := &Member{User: user}
+		byID[user.ID] = member
+		f.Members = append(f.Members, member)
+	}
+	slices.SortFunc(f.Members, func(a, b *Member) int {
+		return cmp.Compare(a.User.ID, b.User.ID)
+	})
+
+	for _, member := range f.Members {
+		if member.User.Parent != nil {
+			if parent := byID[int(*member.User.Parent)]; parent != nil && parent != member {
+				member.Parent = parent
+				parent.Children = append(parent.Children, member)
+				continue
+			}
+		}
+		if f.Head == nil {
+			f.Head = member
+		}
+	}
+	for _, account := range snapshot.Account {
+		f.accounts[account.ID] = account
+	}
+
+	return f
+}
+
+// Member returns the member with the user ID, or nil.
+func (f *Family) Member(id int) *Member {
+	for _, member := range f.Members {
+		if member.User.ID == id {
+			return member
+		}
+	}
+
+	return nil
+}
+
+// AccountMember returns the ID of the member an account belongs to:
+// Account.Role when it is set, and Account.User otherwise.
+func AccountMember(account models.Account) int {
+	if account.Role != nil && *account.Role > 0 {
+		return int(*account.Role)
+	}
+
+	return account.User
+}
+
+// TransactionMember returns the ID of the member a transaction is attributed
+// to: the member who recorded it.
+func TransactionMember(transaction models.Transaction) int {
+	return transaction.User
+}
+
+// Accounts returns the accounts that belong to member, including private
+// ones, in snapshot order.
+func Accounts(snapshot models.Response, member int) []models.Account {
+	var result []models.Account
+	for _, account := range snapshot.Account {
+		if AccountMember(account) == member {
+			result = append(result, account)
+		}
+	}
+
+	return result
+}
+
+// Transactions returns the transactions attributed to member in snapshot
+// order.
+func Transactions(snapshot models.Response, member int) []models.Transaction {
+	var result []models.Transaction
+	for _, transaction := range snapshot.Transaction {
+		if TransactionMember(transaction) == member {
+			result = append(result, transaction)
+		}
+	}
+
+	return result
+}
+
+// Visible reports whether member can see the account with the ID. Accounts
+// are shared with the whole family unless they are private; accounts missing
+// from the snapshot are treated as visible.
+func (f *Family) Visible(accountID string, member int) bool {
+	account, ok := f.accounts[accountID]
+	if !ok {
+		return true
+	}
+
+	return !account.Private || AccountMember(account) == member
+}
+
+// View returns the part of snapshot that member can see. The user list is
+// reduced to the member, private accounts of other members are removed, and so
+// are transactions, reminders, and reminder markers that reference them.
+// Shared entities such as tags, budgets, and merchants are kept.
+func (f *Family) View(snapshot models.Response, member int) models.Response {
+	view := snapshot
+	view.User = slices.DeleteFunc(slices.Clone(snapshot.User), func(user models.User) bool {
+		return user.ID != member
+	})
+	view.Account = slices.DeleteFunc(slices.Clone(snapshot.Account), func(account models.Account) bool {
+		return !f.Visible(account.ID, member)
+	})
+	view.Transaction = slices.DeleteFunc(slices.Clone(snapshot.Transaction), func(transaction models.Transaction) bool {
+		outcomeAccount := ""
+		if transaction.OutcomeAccount != nil {
+			outcomeAccount = *transaction.OutcomeAccount
+		}
+		return !f.visible(member, transaction.IncomeAccount, outcomeAccount)
+	})
+	view.Reminder = slices.DeleteFunc(slices.Clone(snapshot.Reminder), func(reminder models.Reminder) bool {
+		return !f.visible(member, reminder.IncomeAccount, reminder.OutcomeAccount)
+	})
+	view.ReminderMarker = slices.DeleteFunc(slices.Clone(snapshot.ReminderMarker), func(marker models.ReminderMarker) bool {
+		return !f.visible(member, marker.IncomeAccount, marker.OutcomeAccount)
+	})
+
+	return view
+}
+
+func (f *Family) visible(member int, accountIDs ...string) bool {
+	for _, id := range accountIDs {
+		if id != "" && !f.Visible(id, member) {
+			return false
+		}
+	}
+
+	return true
+}
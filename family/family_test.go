@@ -0,0 +1,79 @@
+package family_test
+
+import (
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/family"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshot() models.Response {
+	head := int32(1)
+	spouse := int32(2)
+	cash := "cash"
+	savings := "savings"
+
+	return models.Response{
+		User: []models.User{
+			{ID: 3, Login: "kid", Parent: &head},
+			{ID: 1, Login: "head"},
+			{ID: 2, Login: "spouse", Parent: &head},
+		},
+		Account: []models.Account{
+			{ID: "cash", User: 1},
+			{ID: "card", User: 1, Role: &spouse},
+			{ID: "savings", User: 1, Private: true},
+		},
+		Transaction: []models.Transaction{
+			{ID: "t1", User: 1, IncomeAccount: "cash", OutcomeAccount: &cash},
+			{ID: "t2", User: 2, IncomeAccount: "card", OutcomeAccount: &cash},
+			{ID: "t3", User: 1, IncomeAccount: "savings", OutcomeAccount: &savings},
+		},
+		Reminder: []models.Reminder{
+			{ID: "r1", IncomeAccount: "savings", OutcomeAccount: "cash"},
+		},
+		Tag: []models.Tag{{ID: "food"}},
+	}
+}
+
+func TestFamilyTree(t *testing.T) {
+	f := family.New(snapshot())
+
+	require.Equal(t, 1, f.Head.User.ID)
+	require.Nil(t, f.Head.Parent)
+	require.Len(t, f.Members, 3)
+	require.Equal(t, []*family.Member{f.Member(2), f.Member(3)}, f.Head.Children)
+	require.Same(t, f.Head, f.Member(3).Parent)
+	require.Nil(t, f.Member(4))
+}
+
+func TestAttribution(t *testing.T) {
+	s := snapshot()
+
+	require.Equal(t, 2, family.AccountMember(s.Account[1]))
+	require.Equal(t, 1, family.AccountMember(s.Account[0]))
+	require.Len(t, family.Accounts(s, 1), 2)
+	require.Equal(t, "card", family.Accounts(s, 2)[0].ID)
+	require.Equal(t, []models.Transaction{s.Transaction[1]}, family.Transactions(s, 2))
+}
+
+func TestView(t *testing.T) {
+	s := snapshot()
+	f := family.New(s)
+
+	spouse := f.View(s, 2)
+	require.Len(t, spouse.User, 1)
+	require.Equal(t, 2, spouse.User[0].ID)
+	require.Len(t, spouse.Account, 2)
+	require.Equal(t, []string{"cash", "card"}, []string{spouse.Account[0].ID, spouse.Account[1].ID})
+	require.Len(t, spouse.Transaction, 2)
+	require.Empty(t, spouse.Reminder)
+	require.Equal(t, s.Tag, spouse.Tag)
+
+	head := f.View(s, 1)
+	require.Len(t, head.Account, 3)
+	require.Len(t, head.Transaction, 3)
+	require.Len(t, head.Reminder, 1)
+	require.Len(t, s.Account, 3)
+}
@@ -0,0 +1,311 @@
+// Package geo works with the coordinates recorded on transactions.
+//
+// Many card payments carry Transaction.Latitude and Transaction.Longitude.
+// The package filters such transactions by distance or bounding box, groups
+// nearby spending into clusters, and exports GeoJSON for map visualisation:
+//
+//	nearby := geo.WithinRadius(snapshot.Transaction, geo.Point{Lat: 55.75, Lon: 37.62}, 2000)
+//	err := geo.WriteGeoJSON(w, nearby, geo.Options{Tags: snapshot.Tag})
+//
+// Transactions without both coordinates are skipped by every function.
+package geo
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+// Point is a geographic position in degrees.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Location returns the position of a transaction and reports whether both
+// coordinates are set.
+func Location(transaction models.Transaction) (Point, bool) {
+	if transaction.Latitude == nil || transaction.Longitude == nil {
+		return Point{}, false
+	}
+
+	return Point{Lat: *transaction.Latitude, Lon: *transaction.Longitude}, true
+}
+
+// Distance returns the great-circle distance between two points in meters.
+func Distance(a Point, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat, dLon := lat2-lat1, radians(b.Lon-a.Lon)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// WithinRadius returns the transactions located at most meters from center,
+// in input order.
+func WithinRadius(transactions []models.Transaction, center Point, meters float64) []models.Transaction {
+	return filter(transactions, func(p Point) bool {
+		return Distance(center, p) <= meters
+	})
+}
+
+// Box is a bounding box. A box whose West is greater than its East crosses
+// the antimeridian.
+type Box struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// Contains reports whether p lies inside the box, borders included.
+func (b Box) Contains(p Point) bool {
+	if p.Lat < b.South || p.Lat > b.North {
+		return false
+	}
+	if b.West <= b.East {
+		return p.Lon >= b.West && p.Lon <= b.East
+	}
+
+	return p.Lon >= b.West || p.Lon <= b.East
+}
+
+// WithinBox returns the transactions inside box, in input order.
+func WithinBox(transactions []models.Transaction, box Box) []models.Transaction {
+	return filter(transactions, box.Contains)
+}
+
+func filter(transactions []models.Transaction, keep func(Point) bool) []models.Transaction {
+	var result []models.Transaction
+	for _, transaction := range transactions {
+		if p, ok := Location(transaction); ok && keep(p) {
+			result = append(result, transaction)
+		}
+	}
+
+	return result
+}
+
+// Cluster is a group of transactions made close to each other.
+type Cluster struct {
+	// Center is the mean position of the transactions on the sphere, so
+	// clusters spanning the antimeridian are centred correctly.
+	Center Point
+
+	// Outcome is the total outcome of the transactions by instrument ID.
+	Outcome map[int]float64
+
+	Transactions []models.Transaction
+}
+
+// Clusters groups located transactions so that every transaction is within
+// meters of at least one other transaction of its cluster. Clusters are
+// sorted by descending number of transactions; transactions keep their input
+// order within a cluster.
+func Clusters(transactions []models.Transaction, meters float64) []Cluster {
+	type located struct {
+		transaction models.Transaction
+		point       Point
+		vector      vector
+	}
+	var points []located
+	for _, transaction := range transactions {
+		if p, ok := Location(transaction); ok {
+			points = append(points, located{transaction: transaction, point: p, vector: toVector(p)})
+		}
+	}
+
+	// Union-find over pairs closer than meters. Points are bucketed into a
+	// grid of cubes on the Earth's surface in Cartesian coordinates. The
+	// chord between two points is never longer than the arc, so close pairs
+	// always fall into the same or neighbouring cells.
+	parent := make([]int, len(points))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+	size := math.Max(meters, 1)
+	grid := make(map[cell][]int)
+	for i, p := range points {
+		c := p.vector.cell(size)
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for dz := -1; dz <= 1; dz++ {
+					for _, j := range grid[cell{c[0] + dx, c[1] + dy, c[2] + dz}] {
+						if Distance(p.point, points[j].point) <= meters {
+							parent[root(j)] = root(i)
+						}
+					}
+				}
+			}
+		}
+		grid[c] = append(grid[c], i)
+	}
+
+	type group struct {
+		cluster *Cluster
+		sum     vector
+	}
+	byRoot := make(map[int]*group)
+	var order []int
+	for i, p := range points {
+		r := root(i)
+		g := byRoot[r]
+		if g == nil {
+			g = &group{cluster: &Cluster{Outcome: make(map[int]float64)}}
+			byRoot[r] = g
+			order = append(order, r)
+		}
+		g.cluster.Transactions = append(g.cluster.Transactions, p.transaction)
+		g.sum = vector{g.sum[0] + p.vector[0], g.sum[1] + p.vector[1], g.sum[2] + p.vector[2]}
+		if p.transaction.Outcome > 0 {
+			g.cluster.Outcome[p.transaction.OutcomeInstrument] += p.transaction.Outcome
+		}
+	}
+
+	result := make([]Cluster, 0, len(order))
+	for _, r := range order {
+		g := byRoot[r]
+		g.cluster.Center = g.sum.point()
+		result = append(result, *g.cluster)
+	}
+	slices.SortStableFunc(result, func(a, b Cluster) int {
+		return cmp.Compare(len(b.Transactions), len(a.Transactions))
+	})
+
+	return result
+}
+
+// vector is a position in Earth-centred Cartesian coordinates in meters.
+type vector [3]float64
+
+// cell identifies a cube of the clustering grid.
+type cell [3]int
+
+func toVector(p Point) vector {
+	lat, lon := radians(p.Lat), radians(p.Lon)
+
+	return vector{
+		earthRadius * math.Cos(lat) * math.Cos(lon),
+		earthRadius * math.Cos(lat) * math.Sin(lon),
+		earthRadius * math.Sin(lat),
+	}
+}
+
+func (v vector) cell(size float64) cell {
+	return cell{int(math.Floor(v[0] / size)), int(math.Floor(v[1] / size)), int(math.Floor(v[2] / size))}
+}
+
+// point returns the position of the direction of v. A zero vector, which only
+// a sum of exactly opposite positions produces, maps to latitude and longitude
+// zero.
+func (v vector) point() Point {
+	return Point{
+		Lat: degrees(math.Atan2(v[2], math.Hypot(v[0], v[1]))),
+		Lon: degrees(math.Atan2(v[1], v[0])),
+	}
+}
+
+// Options controls GeoJSON export.
+type Options struct {
+	// Tags resolves tag IDs to titles in the "tags" property. Tags missing
+	// from the list are exported by ID.
+	Tags []models.Tag
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection as defined in RFC 7946.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature with a point geometry.
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry is a GeoJSON Point. Coordinates are longitude, then latitude.
+type Geometry struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// GeoJSON converts located transactions into a FeatureCollection. Every
+// feature has the properties "id", "date", "amount" (outcome, or income when
+// there is no outcome), "instrument", "payee", "comment" when set, and "tags".
+func GeoJSON(transactions []models.Transaction, opts Options) FeatureCollection {
+	tags := make(map[string]string, len(opts.Tags))
+	for _, tag := range opts.Tags {
+		tags[tag.ID] = tag.Title
+	}
+
+	collection := FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	for _, transaction := range transactions {
+		p, ok := Location(transaction)
+		if !ok {
+			continue
+		}
+
+		amount, instrument := transaction.Outcome, transaction.OutcomeInstrument
+		if amount == 0 {
+			amount, instrument = transaction.Income, transaction.IncomeInstrument
+		}
+		names := make([]string, len(transaction.Tag))
+		for i, id := range transaction.Tag {
+			names[i] = cmp.Or(tags[id], id)
+		}
+		properties := map[string]any{
+			"id":         transaction.ID,
+			"date":       transaction.Date,
+			"amount":     amount,
+			"instrument": instrument,
+			"payee":      transaction.Payee,
+			"tags":       names,
+		}
+		if transaction.Comment != nil {
+			properties["comment"] = *transaction.Comment
+		}
+
+		collection.Features = append(collection.Features, Feature{
+			Type:       "Feature",
+			Geometry:   Geometry{Type: "Point", Coordinates: [2]float64{p.Lon, p.Lat}},
+			Properties: properties,
+		})
+	}
+
+	return collection
+}
+
+// WriteGeoJSON writes the GeoJSON of transactions to w. Errors from w are
+// returned wrapped.
+func WriteGeoJSON(w io.Writer, transactions []models.Transaction, opts Options) error {
+	if err := json.NewEncoder(w).Encode(GeoJSON(transactions, opts)); err != nil {
+		return fmt.Errorf("failed to write GeoJSON: %w", err)
+	}
+
+	return nil
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
@@ -0,0 +1,142 @@
+package geo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/geo"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/txtest"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+func transactions() []models.Transaction {
+	return []models.Transaction{
+		txtest.Expense("red-square", "2024-05-01", 100, txtest.At(55.7539, 37.6208)),
+		txtest.Expense("kremlin", "2024-05-01", 200, txtest.At(55.7520, 37.6175)),
+		txtest.Expense("gum", "2024-05-01", 50, txtest.At(55.7547, 37.6215)),
+		txtest.Expense("spb", "2024-05-01", 300, txtest.Payee("Cafe spb"), txtest.Tags("food", "unknown"), txtest.At(59.9343, 30.3351)),
+		{ID: "no-location", Outcome: 10},
+	}
+}
+
+func ids(transactions []models.Transaction) []string {
+	result := make([]string, len(transactions))
+	for i, transaction := range transactions {
+		result[i] = transaction.ID
+	}
+
+	return result
+}
+
+func TestDistance(t *testing.T) {
+	moscow := geo.Point{Lat: 55.7558, Lon: 37.6173}
+	petersburg := geo.Point{Lat: 59.9343, Lon: 30.3351}
+
+	require.InDelta(t, 634_000, geo.Distance(moscow, petersburg), 2_000)
+	require.Zero(t, geo.Distance(moscow, moscow))
+}
+
+func TestFilters(t *testing.T) {
+	center := geo.Point{Lat: 55.7539, Lon: 37.6208}
+
+	require.Equal(t, []string{"red-square", "kremlin", "gum"}, ids(geo.WithinRadius(transactions(), center, 500)))
+	require.Equal(t, []string{"red-square"}, ids(geo.WithinRadius(transactions(), center, 10)))
+	require.Equal(t, []string{"spb"}, ids(geo.WithinBox(transactions(), geo.Box{South: 59, West: 29, North: 61, East: 31})))
+
+	antimeridian := geo.Box{South: -90, West: 170, North: 90, East: -170}
+	require.True(t, antimeridian.Contains(geo.Point{Lat: 0, Lon: 179}))
+	require.True(t, antimeridian.Contains(geo.Point{Lat: 0, Lon: -179}))
+	require.False(t, antimeridian.Contains(geo.Point{Lat: 0, Lon: 0}))
+}
+
+func TestClusters(t *testing.T) {
+	clusters := geo.Clusters(transactions(), 300)
+
+	require.Len(t, clusters, 2)
+	require.Equal(t, []string{"red-square", "kremlin", "gum"}, ids(clusters[0].Transactions))
+	require.Equal(t, map[int]float64{1: 350}, clusters[0].Outcome)
+	require.InDelta(t, 55.7535, clusters[0].Center.Lat, 0.0001)
+	require.Equal(t, []string{"spb"}, ids(clusters[1].Transactions))
+}
+
+func TestClustersChainAcrossCells(t *testing.T) {
+	var chain []models.Transaction
+	for i := range 20 {
+		// Consecutive points are about 111 meters apart along the equator.
+		chain = append(chain, txtest.Expense(fmt.Sprintf("p%d", i), "2024-05-01", 10, txtest.At(0, float64(i)*0.001)))
+	}
+	chain = append(chain, txtest.Expense("far", "2024-05-01", 10, txtest.At(0, 0.5)))
+
+	clusters := geo.Clusters(chain, 150)
+
+	require.Len(t, clusters, 2)
+	require.Len(t, clusters[0].Transactions, 20)
+	require.Equal(t, []string{"far"}, ids(clusters[1].Transactions))
+	require.Len(t, geo.Clusters(chain, 100), 21)
+}
+
+func TestClustersAcrossAntimeridian(t *testing.T) {
+	clusters := geo.Clusters([]models.Transaction{
+		txtest.Expense("east", "2024-05-01", 10, txtest.At(-17, 179.9995)),
+		txtest.Expense("west", "2024-05-01", 10, txtest.At(-17, -179.9995)),
+	}, 300)
+
+	require.Len(t, clusters, 1)
+	require.Len(t, clusters[0].Transactions, 2)
+	require.InDelta(t, -17, clusters[0].Center.Lat, 0.0001)
+	require.InDelta(t, 180, math.Abs(clusters[0].Center.Lon), 0.0001)
+}
+
+func TestWriteGeoJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := geo.WriteGeoJSON(&out, transactions()[3:], geo.Options{Tags: []models.Tag{{ID: "food", Title: "Food"}}})
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [30.3351, 59.9343]},
+			"properties": {
+				"id": "spb",
+				"date": "2024-05-01",
+				"amount": 300,
+				"instrument": 1,
+				"payee": "Cafe spb",
+				"tags": ["Food", "unknown"]
+			}
+		}]
+	}`, out.String())
+
+	var empty geo.FeatureCollection
+	require.NoError(t, json.Unmarshal(mustJSON(t, geo.GeoJSON(nil, geo.Options{})), &empty))
+	require.Equal(t, "FeatureCollection", empty.Type)
+	require.NotNil(t, empty.Features)
+}
+
+func mustJSON(t *testing.T, value any) []byte {
+	t.Helper()
+	payload, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	return payload
+}
+
+var errDiskFull = stdErrors.New("disk full")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errDiskFull
+}
+
+func TestWriteGeoJSONReportsWriterFailure(t *testing.T) {
+	err := geo.WriteGeoJSON(failingWriter{}, transactions(), geo.Options{})
+
+	require.ErrorIs(t, err, errDiskFull)
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	stdErrors "errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+)
+
+type breakerState string
+
+const (
+	breakerClosed   breakerState = "closed"
+	breakerOpen     breakerState = "open"
+	breakerHalfOpen breakerState = "half_open"
+)
+
+// breaker is a circuit breaker shared by all requests of a client. It opens
+// after threshold consecutive server or transport failures, rejects requests
+// during cooldown, and then lets a single trial request through.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	logger    *slog.Logger
+	clock     Clock
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// WithCircuitBreaker enables a circuit breaker that opens after threshold
+// consecutive failures and half-opens after cooldown.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breakerEnabled = true
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
+	}
+}
+
+func newBreaker(threshold int, cooldown time.Duration, logger *slog.Logger, clock Clock) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    logger,
+		clock:     clock,
+		state:     breakerClosed,
+	}
+}
+
+// allow reports whether a request may be sent. A nil breaker allows all
+// requests.
+func (b *breaker) allow(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.cooldown {
+			return errors.New(errors.ErrCircuitOpen, "circuit breaker is open after repeated failures", nil)
+		}
+		b.transition(ctx, breakerHalfOpen)
+		b.trial = true
+		return nil
+	case breakerHalfOpen:
+		if b.trial {
+			return errors.New(errors.ErrCircuitOpen, "circuit breaker is waiting for a trial request", nil)
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of an allowed request. Only
+// server and transport failures, including requests that exceed the client
+// timeout, count; errors caused by the caller's context and other responses
+// from the server close the circuit again.
+func (b *breaker) record(ctx context.Context, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if !countsAsFailure(ctx, err) {
+		if err != nil && callerEnded(ctx) {
+			return
+		}
+		b.failures = 0
+		if b.state != breakerClosed {
+			b.transition(ctx, breakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.clock.Now()
+		b.transition(ctx, breakerOpen)
+	}
+}
+
+// transition changes the state and logs the change. b.mu must be held.
+func (b *breaker) transition(ctx context.Context, state breakerState) {
+	previous := b.state
+	b.state = state
+
+	level := slog.LevelInfo
+	if state == breakerOpen {
+		level = slog.LevelWarn
+	}
+	b.logger.LogAttrs(
+		ctx,
+		level,
+		"ZenMoney circuit breaker state changed",
+		slog.String("from", string(previous)),
+		slog.String("to", string(state)),
+		slog.Int("consecutive_failures", b.failures),
+		slog.Duration("cooldown", b.cooldown),
+	)
+}
+
+func countsAsFailure(ctx context.Context, err error) bool {
+	if err == nil || callerEnded(ctx) {
+		return false
+	}
+
+	var sdkErr *errors.Error
+	if !stdErrors.As(err, &sdkErr) {
+		return false
+	}
+
+	return sdkErr.Code == errors.ErrServerError || sdkErr.Code == errors.ErrNetworkError
+}
+
+// callerEnded reports whether ctx was canceled or timed out by the caller
+// rather than by the client timeout.
+func callerEnded(ctx context.Context) bool {
+	return ctx.Err() != nil && !stdErrors.Is(context.Cause(ctx), errRequestTimeout)
+}
@@ -12,7 +12,9 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
@@ -21,6 +23,11 @@ import (
 
 const maxHTTPErrorBodySnippet int64 = 8 << 10
 
+const (
+	defaultSuggestChunkSize   = 100
+	defaultSuggestConcurrency = 4
+)
+
 // Client represents internal implementation of ZenMoney API client
 type Client struct {
 	baseURL         *url.URL
@@ -31,10 +38,39 @@ type Client struct {
 	retryWaitTime   time.Duration
 	maxResponseSize int64
 	logger          *slog.Logger
+	metrics         Metrics
+	clock           Clock
+	breaker         *breaker
+
+	suggestChunkSize   int
+	suggestConcurrency int
+
+	breakerEnabled   bool
+	breakerThreshold int
+	breakerCooldown  time.Duration
+
+	compressRequests     bool
+	compressionThreshold int
+
+	interceptors          []Interceptor
+	operationInterceptors []Interceptor
+	handler               Handler
+	operationHandler      Handler
+}
+
+// Option configures optional behavior of the internal API client
+type Option func(*Client)
+
+// WithSuggestChunking sets how SuggestAll splits and parallelizes its input
+func WithSuggestChunking(chunkSize int, concurrency int) Option {
+	return func(c *Client) {
+		c.suggestChunkSize = chunkSize
+		c.suggestConcurrency = concurrency
+	}
 }
 
 // NewClient creates a new instance of the internal API client
-func NewClient(token string, baseURL string, httpClient *http.Client, timeout time.Duration, retryAttempts int, retryWaitTime time.Duration, maxResponseSize int64, logger *slog.Logger) (*Client, error) {
+func NewClient(token string, baseURL string, httpClient *http.Client, timeout time.Duration, retryAttempts int, retryWaitTime time.Duration, maxResponseSize int64, logger *slog.Logger, opts ...Option) (*Client, error) {
 	if token == "" {
 		return nil, errors.New(errors.ErrInvalidToken, "token is not provided", nil)
 	}
@@ -70,119 +106,268 @@ func NewClient(token string, baseURL string, httpClient *http.Client, timeout ti
 		logger = slog.New(slog.DiscardHandler)
 	}
 
-	return &Client{
-		baseURL:         parsedBaseURL,
-		token:           token,
-		httpClient:      httpClient,
-		timeout:         timeout,
-		retryAttempts:   retryAttempts,
-		retryWaitTime:   retryWaitTime,
-		maxResponseSize: maxResponseSize,
-		logger:          logger,
-	}, nil
+	c := &Client{
+		baseURL:            parsedBaseURL,
+		token:              token,
+		httpClient:         httpClient,
+		timeout:            timeout,
+		retryAttempts:      retryAttempts,
+		retryWaitTime:      retryWaitTime,
+		maxResponseSize:    maxResponseSize,
+		logger:             logger,
+		suggestChunkSize:   defaultSuggestChunkSize,
+		suggestConcurrency: defaultSuggestConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.metrics == nil {
+		c.metrics = NopMetrics{}
+	}
+	if c.clock == nil {
+		c.clock = SystemClock{}
+	}
+	if c.suggestChunkSize <= 0 {
+		return nil, errors.New(errors.ErrInvalidRequest, "suggest chunk size must be positive", nil)
+	}
+	if c.suggestConcurrency <= 0 {
+		return nil, errors.New(errors.ErrInvalidRequest, "suggest concurrency must be positive", nil)
+	}
+	if c.compressRequests && c.compressionThreshold <= 0 {
+		return nil, errors.New(errors.ErrInvalidRequest, "request compression threshold must be positive", nil)
+	}
+	if c.breakerEnabled {
+		if c.breakerThreshold <= 0 {
+			return nil, errors.New(errors.ErrInvalidRequest, "circuit breaker threshold must be positive", nil)
+		}
+		if c.breakerCooldown <= 0 {
+			return nil, errors.New(errors.ErrInvalidRequest, "circuit breaker cooldown must be positive", nil)
+		}
+		c.breaker = newBreaker(c.breakerThreshold, c.breakerCooldown, c.logger, c.clock)
+	}
+	for _, interceptor := range slices.Concat(c.interceptors, c.operationInterceptors) {
+		if interceptor == nil {
+			return nil, errors.New(errors.ErrInvalidRequest, "interceptor is nil", nil)
+		}
+	}
+	c.handler = chain(c.interceptors, c.attempt)
+	c.operationHandler = chain(c.operationInterceptors, c.retry)
+
+	return c, nil
 }
 
-// sendRequest sends an HTTP request to the specified endpoint with the given method and body
-// It handles retries, timeouts, and response processing
-func (c *Client) sendRequest(ctx context.Context, endpoint string, method string, body any) ([]byte, error) {
-	if ctx == nil {
-		return nil, errors.New(errors.ErrInvalidRequest, "context is nil", nil)
-	}
+// errRequestTimeout is the cause of the context of a request that exceeded
+// the client timeout. It tells the timeout apart from the caller's context.
+var errRequestTimeout = stdErrors.New("request exceeded the client timeout")
 
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, errors.New(errors.ErrInvalidRequest, "failed to marshal request body", err)
+// sendRequest sends body to endpoint and decodes the response into result.
+// The operation runs through the operation interceptors, and each attempt
+// through the attempt interceptors.
+func (c *Client) sendRequest(ctx context.Context, endpoint string, method string, body any, result any) error {
+	if ctx == nil {
+		return errors.New(errors.ErrInvalidRequest, "context is nil", nil)
 	}
 
 	requestCtx := ctx
 	cancel := func() {}
 	if c.timeout > 0 {
-		requestCtx, cancel = context.WithTimeout(ctx, c.timeout)
+		requestCtx, cancel = context.WithTimeoutCause(ctx, c.timeout, errRequestTimeout)
 	}
 	defer cancel()
 
+	err := c.operationHandler(requestCtx, &Call{
+		Method:      method,
+		Endpoint:    endpoint,
+		Request:     body,
+		Response:    result,
+		MaxAttempts: c.retryAttempts + 1,
+	})
+	if err != nil {
+		return sdkError(err)
+	}
+
+	return nil
+}
+
+// retry is the innermost operation Handler. Transport failures are retried;
+// every other outcome is returned.
+func (c *Client) retry(ctx context.Context, operation *Call) error {
 	for attempt := 0; attempt <= c.retryAttempts; attempt++ {
-		attemptNumber := attempt + 1
-		maxAttempts := c.retryAttempts + 1
-		requestURL := c.baseURL.JoinPath(endpoint)
-		req, err := http.NewRequestWithContext(
-			requestCtx,
-			method,
-			requestURL.String(),
-			bytes.NewReader(jsonBody),
+		call := &Call{
+			Method:      operation.Method,
+			Endpoint:    operation.Endpoint,
+			Request:     operation.Request,
+			Response:    operation.Response,
+			Attempt:     attempt + 1,
+			MaxAttempts: operation.MaxAttempts,
+		}
+		err := c.handler(ctx, call)
+		operation.Attempt = call.Attempt
+		operation.StatusCode = call.StatusCode
+		operation.RequestID = call.RequestID
+		operation.ResponseSize = call.ResponseSize
+		if err == nil {
+			return nil
+		}
+		if !call.retryable || attempt == c.retryAttempts {
+			return sdkError(err)
+		}
+
+		c.logger.LogAttrs(
+			ctx,
+			slog.LevelWarn,
+			"ZenMoney HTTP request retry scheduled",
+			slog.String("method", call.Method),
+			slog.String("endpoint", call.Endpoint),
+			slog.Int("attempt", call.Attempt),
+			slog.Int("max_attempts", call.MaxAttempts),
+			slog.Duration("retry_wait", c.retryWaitTime),
 		)
-		if err != nil {
-			return nil, errors.New(errors.ErrInvalidRequest, "failed to create request", err)
+		c.metrics.RetryScheduled(call.Endpoint, c.retryWaitTime)
+
+		if err := waitForRetry(ctx, c.retryWaitTime); err != nil {
+			return errors.New(errors.ErrNetworkError, "retry interrupted", err)
 		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
 
-		startedAt := time.Now()
+	panic("unreachable")
+}
+
+// attempt is the innermost Handler. It encodes call.Request, performs one
+// HTTP exchange and decodes the response body into call.Response.
+func (c *Client) attempt(ctx context.Context, call *Call) error {
+	jsonBody, err := json.Marshal(call.Request)
+	if err != nil {
+		return errors.New(errors.ErrInvalidRequest, "failed to marshal request body", err)
+	}
+	compressed := c.compressRequests && len(jsonBody) >= c.compressionThreshold
+	if compressed {
+		if jsonBody, err = gzipBytes(jsonBody); err != nil {
+			return errors.New(errors.ErrInvalidRequest, "failed to compress request body", err)
+		}
+	}
+
+	requestURL := c.baseURL.JoinPath(call.Endpoint)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		call.Method,
+		requestURL.String(),
+		bytes.NewReader(jsonBody),
+	)
+	if err != nil {
+		return errors.New(errors.ErrInvalidRequest, "failed to create request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept-Encoding", "gzip")
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	if err := c.breaker.allow(ctx); err != nil {
+		c.metrics.RequestCompleted(call.Endpoint, "error", errors.ErrCircuitOpen, 0)
 		c.logger.LogAttrs(
-			requestCtx,
+			ctx,
 			slog.LevelDebug,
-			"ZenMoney HTTP request started",
-			slog.String("method", method),
-			slog.String("endpoint", endpoint),
-			slog.Int("attempt", attemptNumber),
-			slog.Int("max_attempts", maxAttempts),
+			"ZenMoney HTTP request rejected",
+			slog.String("method", call.Method),
+			slog.String("endpoint", call.Endpoint),
+			slog.Int("attempt", call.Attempt),
+			slog.Int("max_attempts", call.MaxAttempts),
+			slog.String("error_code", string(errors.ErrCircuitOpen)),
 		)
+		return err
+	}
 
-		resp, requestErr := c.httpClient.Do(req)
-		if requestErr == nil {
-			resBody, responseErr := readResponse(resp, c.maxResponseSize)
-			attrs := []slog.Attr{
-				slog.String("method", method),
-				slog.String("endpoint", endpoint),
-				slog.Int("attempt", attemptNumber),
-				slog.Int("max_attempts", maxAttempts),
-				slog.Duration("duration", time.Since(startedAt)),
-				slog.Int("status_code", responseStatusCode(resp)),
-				slog.String("request_id", responseRequestID(responseHeader(resp))),
-				slog.String("outcome", "success"),
-			}
-			if responseErr != nil {
-				attrs[len(attrs)-1] = slog.String("outcome", "error")
-				var sdkErr *errors.Error
-				if stdErrors.As(responseErr, &sdkErr) {
-					attrs = append(attrs, slog.String("error_code", string(sdkErr.Code)))
-				}
-			}
-			c.logger.LogAttrs(requestCtx, slog.LevelDebug, "ZenMoney HTTP request completed", attrs...)
+	startedAt := time.Now()
+	c.logger.LogAttrs(
+		ctx,
+		slog.LevelDebug,
+		"ZenMoney HTTP request started",
+		slog.String("method", call.Method),
+		slog.String("endpoint", call.Endpoint),
+		slog.Int("attempt", call.Attempt),
+		slog.Int("max_attempts", call.MaxAttempts),
+	)
 
-			return resBody, responseErr
-		}
+	resp, requestErr := c.httpClient.Do(req)
+	if requestErr != nil {
 		closeResponse(resp)
+		c.breaker.record(ctx, errors.New(errors.ErrNetworkError, "failed to send request", requestErr))
 
-		if requestCtx.Err() != nil {
-			c.logTransportFailure(requestCtx, method, endpoint, attemptNumber, maxAttempts, startedAt, "context_ended")
-			return nil, errors.New(errors.ErrNetworkError, "request context ended", requestCtx.Err())
+		if ctx.Err() != nil {
+			c.logTransportFailure(ctx, call.Method, call.Endpoint, call.Attempt, call.MaxAttempts, startedAt, "context_ended")
+			return errors.New(errors.ErrNetworkError, "request context ended", ctx.Err())
 		}
-		if attempt == c.retryAttempts {
-			c.logTransportFailure(requestCtx, method, endpoint, attemptNumber, maxAttempts, startedAt, "error")
-			return nil, errors.New(errors.ErrNetworkError, "failed to send request after retries", requestErr)
+		if call.Attempt == call.MaxAttempts {
+			c.logTransportFailure(ctx, call.Method, call.Endpoint, call.Attempt, call.MaxAttempts, startedAt, "error")
+			return errors.New(errors.ErrNetworkError, "failed to send request after retries", requestErr)
 		}
+		c.metrics.RequestCompleted(call.Endpoint, "error", errors.ErrNetworkError, time.Since(startedAt))
+		call.retryable = true
+		return errors.New(errors.ErrNetworkError, "failed to send request", requestErr)
+	}
+
+	resBody, received, responseErr := readResponse(resp, c.maxResponseSize)
+	call.StatusCode = responseStatusCode(resp)
+	call.RequestID = responseRequestID(responseHeader(resp))
+	call.ResponseSize = received
+	duration := time.Since(startedAt)
+	attrs := []slog.Attr{
+		slog.String("method", call.Method),
+		slog.String("endpoint", call.Endpoint),
+		slog.Int("attempt", call.Attempt),
+		slog.Int("max_attempts", call.MaxAttempts),
+		slog.Duration("duration", duration),
+		slog.Int("status_code", call.StatusCode),
+		slog.String("request_id", call.RequestID),
+		slog.String("outcome", "success"),
+	}
+	if responseErr != nil {
+		attrs[len(attrs)-1] = slog.String("outcome", "error")
+		var code errors.ErrorCode
+		var sdkErr *errors.Error
+		if stdErrors.As(responseErr, &sdkErr) {
+			code = sdkErr.Code
+			attrs = append(attrs, slog.String("error_code", string(sdkErr.Code)))
+		}
+		c.metrics.RequestCompleted(call.Endpoint, "error", code, duration)
+	} else {
+		c.metrics.RequestCompleted(call.Endpoint, "success", "", duration)
+		c.metrics.BytesReceived(call.Endpoint, call.ResponseSize)
+	}
+	c.breaker.record(ctx, responseErr)
+	if call.StatusCode == http.StatusTooManyRequests {
+		c.metrics.RateLimited(call.Endpoint, retryAfter(responseHeader(resp), c.clock.Now()))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "ZenMoney HTTP request completed", attrs...)
+	if responseErr != nil {
+		return responseErr
+	}
 
-		c.logger.LogAttrs(
-			requestCtx,
-			slog.LevelWarn,
-			"ZenMoney HTTP request retry scheduled",
-			slog.String("method", method),
-			slog.String("endpoint", endpoint),
-			slog.Int("attempt", attemptNumber),
-			slog.Int("max_attempts", maxAttempts),
-			slog.Duration("retry_wait", c.retryWaitTime),
-		)
-
-		if err := waitForRetry(requestCtx, c.retryWaitTime); err != nil {
-			return nil, errors.New(errors.ErrNetworkError, "retry interrupted", err)
+	if call.Response != nil {
+		if err := json.Unmarshal(resBody, call.Response); err != nil {
+			return errors.New(errors.ErrInvalidRequest, "failed to unmarshal response", err)
 		}
 	}
 
-	panic("unreachable")
+	return nil
+}
+
+// sdkError returns err unchanged when it is an *errors.Error and wraps errors
+// returned by interceptors otherwise.
+func sdkError(err error) error {
+	var sdkErr *errors.Error
+	if stdErrors.As(err, &sdkErr) {
+		return err
+	}
+
+	return errors.New(errors.ErrInvalidRequest, "request interceptor failed", err)
 }
 
 func (c *Client) logTransportFailure(ctx context.Context, method string, endpoint string, attempt int, maxAttempts int, startedAt time.Time, outcome string) {
+	duration := time.Since(startedAt)
+	c.metrics.RequestCompleted(endpoint, outcome, errors.ErrNetworkError, duration)
 	c.logger.LogAttrs(
 		ctx,
 		slog.LevelDebug,
@@ -191,7 +376,7 @@ func (c *Client) logTransportFailure(ctx context.Context, method string, endpoin
 		slog.String("endpoint", endpoint),
 		slog.Int("attempt", attempt),
 		slog.Int("max_attempts", maxAttempts),
-		slog.Duration("duration", time.Since(startedAt)),
+		slog.Duration("duration", duration),
 		slog.String("outcome", outcome),
 		slog.String("error_code", string(errors.ErrNetworkError)),
 	)
@@ -229,34 +414,48 @@ func waitForRetry(ctx context.Context, waitTime time.Duration) error {
 	}
 }
 
-func readResponse(resp *http.Response, maxResponseSize int64) ([]byte, error) {
+// readResponse reads and decodes a successful response body. It also returns
+// the number of body bytes received on the wire, before decompression.
+func readResponse(resp *http.Response, maxResponseSize int64) ([]byte, int64, error) {
 	if resp == nil {
-		return nil, errors.New(errors.ErrNetworkError, "got nil response", nil)
+		return nil, 0, errors.New(errors.ErrNetworkError, "got nil response", nil)
 	}
 	if resp.Body == nil {
-		return nil, errors.New(errors.ErrNetworkError, "got response with nil body", nil)
+		return nil, 0, errors.New(errors.ErrNetworkError, "got response with nil body", nil)
 	}
 	if resp.StatusCode >= http.StatusBadRequest {
-		return nil, readHTTPError(resp)
+		return nil, 0, readHTTPError(resp)
 	}
 
-	resBody, readErr := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	wire := &countingReader{reader: resp.Body}
+	body, err := decodedBody(resp.Header, wire)
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, wire.count, errors.New(errors.ErrNetworkError, "failed to decompress response body", err)
+	}
+	// The limit applies to the decoded body so that a small compressed
+	// response cannot expand beyond maxResponseSize.
+	resBody, readErr := io.ReadAll(io.LimitReader(body, maxResponseSize+1))
 	closeErr := resp.Body.Close()
 	if readErr != nil {
-		return nil, errors.New(errors.ErrNetworkError, "failed to read response body", readErr)
+		return nil, wire.count, errors.New(errors.ErrNetworkError, "failed to read response body", readErr)
 	}
 	if int64(len(resBody)) > maxResponseSize {
-		return nil, errors.New(errors.ErrResponseTooLarge, "response body exceeds configured limit", nil)
+		return nil, wire.count, errors.New(errors.ErrResponseTooLarge, "response body exceeds configured limit", nil)
 	}
 	if closeErr != nil {
-		return nil, errors.New(errors.ErrNetworkError, "failed to close response body", closeErr)
+		return nil, wire.count, errors.New(errors.ErrNetworkError, "failed to close response body", closeErr)
 	}
 
-	return resBody, nil
+	return resBody, wire.count, nil
 }
 
 func readHTTPError(resp *http.Response) error {
-	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxHTTPErrorBodySnippet+1))
+	var body []byte
+	decoded, readErr := decodedBody(resp.Header, resp.Body)
+	if readErr == nil {
+		body, readErr = io.ReadAll(io.LimitReader(decoded, maxHTTPErrorBodySnippet+1))
+	}
 	truncated := int64(len(body)) > maxHTTPErrorBodySnippet
 	if truncated {
 		body = body[:maxHTTPErrorBodySnippet]
@@ -311,15 +510,9 @@ func errorCodeForStatus(statusCode int) errors.ErrorCode {
 
 // Sync sends a synchronization request to ZenMoney API with the provided parameters
 func (c *Client) Sync(ctx context.Context, body models.Request) (models.Response, error) {
-	resBody, err := c.sendRequest(ctx, "diff/", http.MethodPost, body)
-	if err != nil {
-		return models.Response{}, err
-	}
-
 	var result models.Response
-	if err := json.Unmarshal(resBody, &result); err != nil {
-		return models.Response{}, errors.New(errors.ErrInvalidRequest,
-			"failed to unmarshal response", err)
+	if err := c.sendRequest(ctx, "diff/", http.MethodPost, body, &result); err != nil {
+		return models.Response{}, err
 	}
 
 	return result, nil
@@ -328,7 +521,7 @@ func (c *Client) Sync(ctx context.Context, body models.Request) (models.Response
 // FullSync performs a full synchronization with ZenMoney API, retrieving all available data
 func (c *Client) FullSync(ctx context.Context) (models.Response, error) {
 	body := models.Request{
-		CurrentClientTimestamp: time.Now().Unix(),
+		CurrentClientTimestamp: c.clock.Now().Unix(),
 		ServerTimestamp:        0,
 	}
 
@@ -338,18 +531,24 @@ func (c *Client) FullSync(ctx context.Context) (models.Response, error) {
 // SyncSince performs a synchronization with ZenMoney API from the specified timestamp
 func (c *Client) SyncSince(ctx context.Context, lastSync time.Time) (models.Response, error) {
 	body := models.Request{
-		CurrentClientTimestamp: time.Now().Unix(),
+		CurrentClientTimestamp: c.clock.Now().Unix(),
 		ServerTimestamp:        lastSync.Unix(),
 	}
 
 	return c.Sync(ctx, body)
 }
 
+// ForceSyncEntities requests all specified entities using the current time
+// of the client clock as the server timestamp.
+func (c *Client) ForceSyncEntities(ctx context.Context, entityTypes ...models.EntityType) (models.Response, error) {
+	return c.ForceSyncEntitiesSince(ctx, c.clock.Now(), entityTypes...)
+}
+
 // ForceSyncEntitiesSince requests all specified entities and regular changes
 // since the server timestamp returned by the previous synchronization.
 func (c *Client) ForceSyncEntitiesSince(ctx context.Context, lastSync time.Time, entityTypes ...models.EntityType) (models.Response, error) {
 	body := models.Request{
-		CurrentClientTimestamp: time.Now().Unix(),
+		CurrentClientTimestamp: c.clock.Now().Unix(),
 		ServerTimestamp:        lastSync.Unix(),
 		ForceFetch:             entityTypes,
 	}
@@ -369,15 +568,9 @@ func (c *Client) ForceSyncEntitiesSince(ctx context.Context, lastSync time.Time,
 //   - Transaction: Transaction object with suggested values
 //   - error: Any error that occurred during the request
 func (c *Client) Suggest(ctx context.Context, transaction models.Transaction) (models.Transaction, error) {
-	resBody, err := c.sendRequest(ctx, "suggest/", http.MethodPost, transaction)
-	if err != nil {
-		return models.Transaction{}, err
-	}
-
 	var result models.Transaction
-	if err := json.Unmarshal(resBody, &result); err != nil {
-		return models.Transaction{}, errors.New(errors.ErrInvalidRequest,
-			"failed to unmarshal response", err)
+	if err := c.sendRequest(ctx, "suggest/", http.MethodPost, transaction, &result); err != nil {
+		return models.Transaction{}, err
 	}
 
 	return result, nil
@@ -385,16 +578,66 @@ func (c *Client) Suggest(ctx context.Context, transaction models.Transaction) (m
 
 // SuggestBatch sends a batch suggestion request to the ZenMoney API for multiple transactions.
 func (c *Client) SuggestBatch(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
-	resBody, err := c.sendRequest(ctx, "suggest/", http.MethodPost, transactions)
-	if err != nil {
+	var result []models.Transaction
+	if err := c.sendRequest(ctx, "suggest/", http.MethodPost, transactions, &result); err != nil {
 		return nil, err
 	}
 
-	var result []models.Transaction
-	if err := json.Unmarshal(resBody, &result); err != nil {
-		return nil, errors.New(errors.ErrInvalidRequest,
-			"failed to unmarshal response", err)
+	return result, nil
+}
+
+// SuggestAll splits transactions into chunks, requests suggestions for the
+// chunks concurrently and merges the results in input order. Positions of
+// failed chunks keep the input transactions and are reported in *errors.BatchError.
+func (c *Client) SuggestAll(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
+	if ctx == nil {
+		return nil, errors.New(errors.ErrInvalidRequest, "context is nil", nil)
 	}
 
-	return result, nil
+	results := slices.Clone(transactions)
+	failures := make(map[int]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.suggestConcurrency)
+
+	for chunk, start := 0, 0; start < len(transactions); chunk, start = chunk+1, start+c.suggestChunkSize {
+		end := min(start+c.suggestChunkSize, len(transactions))
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			failures[chunk] = errors.New(errors.ErrNetworkError, "request context ended", ctx.Err())
+			mu.Unlock()
+			continue
+		}
+
+		wg.Go(func() {
+			defer func() { <-semaphore }()
+
+			suggestions, err := c.SuggestBatch(ctx, transactions[start:end])
+			if err == nil && len(suggestions) != end-start {
+				err = errors.New(errors.ErrInvalidRequest,
+					fmt.Sprintf("got %d suggestions for %d transactions", len(suggestions), end-start), nil)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures[chunk] = err
+				return
+			}
+			copy(results[start:end], suggestions)
+		})
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return results, &errors.BatchError{
+			ChunkSize: c.suggestChunkSize,
+			Chunks:    failures,
+		}
+	}
+
+	return results, nil
 }
@@ -1,13 +1,18 @@
 package client
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	stdErrors "errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -24,6 +29,14 @@ func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
 func setupTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *Client) {
 	server := httptest.NewServer(handler)
 
@@ -623,6 +636,129 @@ func TestSuggestBatch(t *testing.T) {
 	})
 }
 
+func TestSuggestAll(t *testing.T) {
+	newClient := func(t *testing.T, handler http.HandlerFunc, chunkSize int, concurrency int) *Client {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+
+		client, err := NewClient(
+			"test-token",
+			server.URL+"/",
+			&http.Client{},
+			time.Second,
+			0,
+			0,
+			testMaxResponseSize,
+			nil,
+			WithSuggestChunking(chunkSize, concurrency),
+		)
+		require.NoError(t, err)
+
+		return client
+	}
+	echo := func(r *http.Request) []models.Transaction {
+		var reqTxs []models.Transaction
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqTxs))
+		for i := range reqTxs {
+			reqTxs[i].Tag = []string{"tag-" + reqTxs[i].Payee}
+		}
+		return reqTxs
+	}
+	input := func(n int) []models.Transaction {
+		txs := make([]models.Transaction, n)
+		for i := range txs {
+			txs[i].Payee = strconv.Itoa(i)
+		}
+		return txs
+	}
+
+	t.Run("splits input and preserves order", func(t *testing.T) {
+		var mu sync.Mutex
+		var sizes []int
+		active, maxActive := 0, 0
+		client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			active++
+			maxActive = max(maxActive, active)
+			mu.Unlock()
+
+			suggestions := echo(r)
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			active--
+			sizes = append(sizes, len(suggestions))
+			mu.Unlock()
+			require.NoError(t, json.NewEncoder(w).Encode(suggestions))
+		}, 3, 2)
+
+		suggestions, err := client.SuggestAll(context.Background(), input(10))
+
+		require.NoError(t, err)
+		require.Len(t, suggestions, 10)
+		for i, suggestion := range suggestions {
+			require.Equal(t, []string{"tag-" + strconv.Itoa(i)}, suggestion.Tag)
+		}
+		require.ElementsMatch(t, []int{3, 3, 3, 1}, sizes)
+		require.LessOrEqual(t, maxActive, 2)
+	})
+
+	t.Run("returns partial results with failed chunks", func(t *testing.T) {
+		client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			suggestions := echo(r)
+			if suggestions[0].Payee == "2" {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(suggestions))
+		}, 2, 1)
+
+		suggestions, err := client.SuggestAll(context.Background(), input(5))
+
+		var batchErr *errors.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, 2, batchErr.ChunkSize)
+		require.Len(t, batchErr.Chunks, 1)
+		require.Equal(t, errors.ErrServerError, batchErr.Chunks[1].(*errors.Error).Code)
+		require.Equal(t, []string{"tag-0"}, suggestions[0].Tag)
+		require.Nil(t, suggestions[2].Tag)
+		require.Equal(t, "2", suggestions[2].Payee)
+		require.Equal(t, []string{"tag-4"}, suggestions[4].Tag)
+	})
+
+	t.Run("rejects mismatched result count", func(t *testing.T) {
+		client := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewEncoder(w).Encode(echo(r)[:1]))
+		}, 2, 1)
+
+		_, err := client.SuggestAll(context.Background(), input(2))
+
+		var batchErr *errors.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, errors.ErrInvalidRequest, batchErr.Chunks[0].(*errors.Error).Code)
+	})
+
+	t.Run("handles empty input", func(t *testing.T) {
+		client := newClient(t, func(http.ResponseWriter, *http.Request) {
+			t.Fatal("unexpected request")
+		}, 2, 1)
+
+		suggestions, err := client.SuggestAll(context.Background(), nil)
+
+		require.NoError(t, err)
+		require.Empty(t, suggestions)
+	})
+
+	t.Run("validates chunking", func(t *testing.T) {
+		for _, opt := range []Option{WithSuggestChunking(0, 1), WithSuggestChunking(1, 0)} {
+			client, err := NewClient("test-token", "https://api.test.com/", &http.Client{}, time.Second, 0, 0, testMaxResponseSize, nil, opt)
+
+			require.Nil(t, client)
+			require.Equal(t, errors.ErrInvalidRequest, err.(*errors.Error).Code)
+		}
+	})
+}
+
 func TestFullSync(t *testing.T) {
 	t.Run("successful full sync with correct request", func(t *testing.T) {
 		server, client := setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
@@ -836,3 +972,447 @@ func TestForceSyncEntitiesSince(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestInterceptors(t *testing.T) {
+	newClient := func(t *testing.T, transport roundTripFunc, retryAttempts int, interceptors ...Interceptor) *Client {
+		t.Helper()
+		client, err := NewClient(
+			"test-token",
+			"https://api.test.com/",
+			&http.Client{Transport: transport},
+			time.Second,
+			retryAttempts,
+			0,
+			testMaxResponseSize,
+			nil,
+			WithInterceptors(interceptors...),
+		)
+		require.NoError(t, err)
+		return client
+	}
+	respond := func(body string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+
+	t.Run("runs in order around every attempt", func(t *testing.T) {
+		var trace []string
+		requests := 0
+		record := func(name string) Interceptor {
+			return func(ctx context.Context, call *Call, next Handler) error {
+				trace = append(trace, name+" before "+strconv.Itoa(call.Attempt)+"/"+strconv.Itoa(call.MaxAttempts))
+				err := next(ctx, call)
+				code := "ok"
+				var sdkErr *errors.Error
+				if stdErrors.As(err, &sdkErr) {
+					code = string(sdkErr.Code)
+				}
+				trace = append(trace, name+" after "+code)
+				return err
+			}
+		}
+		client := newClient(t, func(*http.Request) (*http.Response, error) {
+			requests++
+			if requests == 1 {
+				return nil, stdErrors.New("temporary network error")
+			}
+			return respond(`{"serverTimestamp":100}`)
+		}, 1, record("outer"), record("inner"))
+
+		resp, err := client.Sync(context.Background(), models.Request{})
+
+		require.NoError(t, err)
+		require.Equal(t, int64(100), resp.ServerTimestamp)
+		require.Equal(t, []string{
+			"outer before 1/2", "inner before 1/2", "inner after NETWORK_ERROR", "outer after NETWORK_ERROR",
+			"outer before 2/2", "inner before 2/2", "inner after ok", "outer after ok",
+		}, trace)
+	})
+
+	t.Run("sees decoded values and may replace the request", func(t *testing.T) {
+		var sentBody models.Request
+		client := newClient(t, func(req *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&sentBody))
+			return respond(`{"serverTimestamp":200}`)
+		}, 0, func(ctx context.Context, call *Call, next Handler) error {
+			require.Equal(t, "diff/", call.Endpoint)
+			require.Equal(t, http.MethodPost, call.Method)
+			request := call.Request.(models.Request)
+			request.ForceFetch = []models.EntityType{models.EntityTypeTag}
+			call.Request = request
+
+			if err := next(ctx, call); err != nil {
+				return err
+			}
+			require.Equal(t, int64(200), call.Response.(*models.Response).ServerTimestamp)
+			return nil
+		})
+
+		_, err := client.Sync(context.Background(), models.Request{ServerTimestamp: 10})
+
+		require.NoError(t, err)
+		require.Equal(t, int64(10), sentBody.ServerTimestamp)
+		require.Equal(t, []models.EntityType{models.EntityTypeTag}, sentBody.ForceFetch)
+	})
+
+	t.Run("short-circuits without a request", func(t *testing.T) {
+		client := newClient(t, func(*http.Request) (*http.Response, error) {
+			t.Fatal("unexpected HTTP request")
+			return nil, nil
+		}, 0, func(_ context.Context, call *Call, _ Handler) error {
+			*call.Response.(*[]models.Transaction) = []models.Transaction{{ID: "cached"}}
+			return nil
+		})
+
+		suggestions, err := client.SuggestBatch(context.Background(), []models.Transaction{{Payee: "Coffee"}})
+
+		require.NoError(t, err)
+		require.Equal(t, []models.Transaction{{ID: "cached"}}, suggestions)
+	})
+
+	t.Run("wraps foreign errors and does not retry them", func(t *testing.T) {
+		calls := 0
+		denied := stdErrors.New("denied by policy")
+		client := newClient(t, func(*http.Request) (*http.Response, error) {
+			t.Fatal("unexpected HTTP request")
+			return nil, nil
+		}, 3, func(context.Context, *Call, Handler) error {
+			calls++
+			return denied
+		})
+
+		_, err := client.Suggest(context.Background(), models.Transaction{})
+
+		var sdkErr *errors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, errors.ErrInvalidRequest, sdkErr.Code)
+		require.ErrorIs(t, err, denied)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("runs operation interceptors once around retries", func(t *testing.T) {
+		requests := 0
+		client, err := NewClient(
+			"test-token",
+			"https://api.test.com/",
+			&http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				requests++
+				if requests == 1 {
+					return nil, stdErrors.New("temporary network error")
+				}
+				resp, err := respond(`{"serverTimestamp":100}`)
+				resp.Header.Set("X-Request-ID", "request-123")
+				return resp, err
+			})},
+			time.Second,
+			2,
+			0,
+			testMaxResponseSize,
+			nil,
+			WithOperationInterceptors(func(ctx context.Context, call *Call, next Handler) error {
+				require.Zero(t, call.Attempt)
+				require.Equal(t, 3, call.MaxAttempts)
+				err := next(ctx, call)
+				require.Equal(t, 2, call.Attempt)
+				require.Equal(t, http.StatusOK, call.StatusCode)
+				require.Equal(t, "request-123", call.RequestID)
+				require.Equal(t, int64(len(`{"serverTimestamp":100}`)), call.ResponseSize)
+				return err
+			}),
+		)
+		require.NoError(t, err)
+
+		_, err = client.FullSync(context.Background())
+
+		require.NoError(t, err)
+		require.Equal(t, 2, requests)
+	})
+
+	t.Run("rejects nil interceptor", func(t *testing.T) {
+		_, err := NewClient(
+			"test-token",
+			"https://api.test.com/",
+			&http.Client{},
+			time.Second,
+			0,
+			0,
+			testMaxResponseSize,
+			nil,
+			WithInterceptors(nil),
+		)
+
+		var sdkErr *errors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, errors.ErrInvalidRequest, sdkErr.Code)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "missing", want: 0},
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "negative seconds", value: "-5", want: 0},
+		{name: "HTTP date", value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{name: "past date", value: now.Add(-time.Hour).Format(http.TimeFormat), want: 0},
+		{name: "malformed", value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+
+			require.Equal(t, tt.want, retryAfter(header, now))
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var logs strings.Builder
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	requests := 0
+	status := http.StatusServiceUnavailable
+	clock := &fixedClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	client, err := NewClient(
+		"test-token",
+		"https://api.test.com/",
+		&http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{
+				StatusCode: status,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+			}, nil
+		})},
+		time.Second,
+		0,
+		0,
+		testMaxResponseSize,
+		logger,
+		WithCircuitBreaker(2, time.Minute),
+		WithClock(clock),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	requireCode := func(t *testing.T, err error, code errors.ErrorCode) {
+		t.Helper()
+		var sdkErr *errors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, code, sdkErr.Code)
+	}
+
+	_, err = client.FullSync(ctx)
+	requireCode(t, err, errors.ErrServerError)
+	_, err = client.FullSync(ctx)
+	requireCode(t, err, errors.ErrServerError)
+	require.Contains(t, logs.String(), "from=closed to=open")
+
+	_, err = client.FullSync(ctx)
+	requireCode(t, err, errors.ErrCircuitOpen)
+	require.Equal(t, 2, requests)
+
+	clock.now = clock.now.Add(time.Minute)
+	_, err = client.FullSync(ctx)
+	requireCode(t, err, errors.ErrServerError)
+	require.Equal(t, 3, requests)
+	require.Contains(t, logs.String(), "from=half_open to=open")
+
+	_, err = client.FullSync(ctx)
+	requireCode(t, err, errors.ErrCircuitOpen)
+
+	clock.now = clock.now.Add(time.Minute)
+	status = http.StatusOK
+	_, err = client.FullSync(ctx)
+	require.NoError(t, err)
+	require.Contains(t, logs.String(), "from=half_open to=closed")
+
+	status = http.StatusServiceUnavailable
+	_, err = client.FullSync(ctx)
+	requireCode(t, err, errors.ErrServerError)
+	_, err = client.FullSync(ctx)
+	requireCode(t, err, errors.ErrServerError)
+	require.Equal(t, 6, requests)
+}
+
+func TestCircuitBreakerOpensOnClientTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	client, err := NewClient(
+		"test-token",
+		server.URL+"/",
+		server.Client(),
+		50*time.Millisecond,
+		0,
+		0,
+		testMaxResponseSize,
+		slog.New(slog.DiscardHandler),
+		WithCircuitBreaker(2, time.Minute),
+	)
+	require.NoError(t, err)
+
+	codes := make([]errors.ErrorCode, 3)
+	for i := range codes {
+		_, err := client.FullSync(context.Background())
+		var sdkErr *errors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		codes[i] = sdkErr.Code
+	}
+
+	require.Equal(t, []errors.ErrorCode{errors.ErrNetworkError, errors.ErrNetworkError, errors.ErrCircuitOpen}, codes)
+}
+
+func TestCircuitBreakerIgnoresCallerCancellation(t *testing.T) {
+	b := newBreaker(1, time.Minute, slog.New(slog.DiscardHandler), SystemClock{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, b.allow(ctx))
+	b.record(ctx, errors.New(errors.ErrNetworkError, "request context ended", ctx.Err()))
+	require.Equal(t, breakerClosed, b.state)
+
+	b.record(context.Background(), errors.New(errors.ErrInvalidRequest, "bad request", nil))
+	require.Equal(t, breakerClosed, b.state)
+	b.record(context.Background(), errors.New(errors.ErrNetworkError, "connection refused", nil))
+	require.Equal(t, breakerOpen, b.state)
+}
+
+func TestCompression(t *testing.T) {
+	gzipResponse := func(t *testing.T, status int, body string) *http.Response {
+		t.Helper()
+		compressed, err := gzipBytes([]byte(body))
+		require.NoError(t, err)
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+			Body:       io.NopCloser(bytes.NewReader(compressed)),
+		}
+	}
+	newClient := func(t *testing.T, maxResponseSize int64, transport roundTripFunc, opts ...Option) *Client {
+		t.Helper()
+		client, err := NewClient(
+			"test-token",
+			"https://api.test.com/",
+			&http.Client{Transport: transport},
+			time.Second,
+			0,
+			0,
+			maxResponseSize,
+			nil,
+			opts...,
+		)
+		require.NoError(t, err)
+		return client
+	}
+
+	t.Run("decompresses gzip responses", func(t *testing.T) {
+		body := `{"serverTimestamp":42,"tag":[` + strings.Repeat(`{"id":"x"},`, 100) + `{"id":"x"}]}`
+		compressed, err := gzipBytes([]byte(body))
+		require.NoError(t, err)
+		var received int64
+		client := newClient(t, testMaxResponseSize, func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "gzip", req.Header.Get("Accept-Encoding"))
+			require.Empty(t, req.Header.Get("Content-Encoding"))
+			return gzipResponse(t, http.StatusOK, body), nil
+		}, WithInterceptors(func(ctx context.Context, call *Call, next Handler) error {
+			err := next(ctx, call)
+			received = call.ResponseSize
+			return err
+		}))
+
+		resp, err := client.FullSync(context.Background())
+
+		require.NoError(t, err)
+		require.Equal(t, int64(42), resp.ServerTimestamp)
+		require.Equal(t, int64(len(compressed)), received)
+		require.Less(t, received, int64(len(body)))
+	})
+
+	t.Run("limits the decompressed size", func(t *testing.T) {
+		body := `{"serverTimestamp":1,"tag":[` + strings.Repeat(`{"id":"x"},`, 10_000) + `{"id":"x"}]}`
+		client := newClient(t, 1024, func(*http.Request) (*http.Response, error) {
+			return gzipResponse(t, http.StatusOK, body), nil
+		})
+
+		_, err := client.FullSync(context.Background())
+
+		var sdkErr *errors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, errors.ErrResponseTooLarge, sdkErr.Code)
+	})
+
+	t.Run("decompresses error bodies", func(t *testing.T) {
+		client := newClient(t, testMaxResponseSize, func(*http.Request) (*http.Response, error) {
+			return gzipResponse(t, http.StatusBadRequest, `invalid transaction`), nil
+		})
+
+		_, err := client.FullSync(context.Background())
+
+		var sdkErr *errors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, "invalid transaction", sdkErr.BodySnippet)
+	})
+
+	t.Run("rejects corrupt gzip responses", func(t *testing.T) {
+		client := newClient(t, testMaxResponseSize, func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Encoding": []string{"gzip"}},
+				Body:       io.NopCloser(strings.NewReader(`not gzip`)),
+			}, nil
+		})
+
+		_, err := client.FullSync(context.Background())
+
+		var sdkErr *errors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, errors.ErrNetworkError, sdkErr.Code)
+	})
+
+	t.Run("compresses large request bodies", func(t *testing.T) {
+		var encodings []string
+		var payees []string
+		client := newClient(t, testMaxResponseSize, func(req *http.Request) (*http.Response, error) {
+			encodings = append(encodings, req.Header.Get("Content-Encoding"))
+			var body io.Reader = req.Body
+			if req.Header.Get("Content-Encoding") == "gzip" {
+				reader, err := gzip.NewReader(req.Body)
+				require.NoError(t, err)
+				body = reader
+			}
+			var transaction models.Transaction
+			require.NoError(t, json.NewDecoder(body).Decode(&transaction))
+			payees = append(payees, transaction.Payee)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+			}, nil
+		}, WithRequestCompression(2048))
+
+		large := strings.Repeat("p", 4096)
+		_, err := client.Suggest(context.Background(), models.Transaction{Payee: "small"})
+		require.NoError(t, err)
+		_, err = client.Suggest(context.Background(), models.Transaction{Payee: large})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"", "gzip"}, encodings)
+		require.Equal(t, []string{"small", large}, payees)
+	})
+}
@@ -0,0 +1,20 @@
+package client
+
+import "github.com/nemirlev/zenmoney-go-sdk/v3/models"
+
+// Clock provides the current time. It is the Clock of the models package, so
+// the same value can be passed to the client, the entity constructors, and
+// the file store.
+type Clock = models.Clock
+
+// SystemClock reads the time from the operating system.
+type SystemClock = models.SystemClock
+
+// WithClock sets the clock used for request timestamps, Retry-After dates, and
+// circuit breaker cooldowns. Request durations are measured with the
+// monotonic system clock. A nil clock restores SystemClock.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
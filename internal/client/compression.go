@@ -0,0 +1,54 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WithRequestCompression gzips request bodies of at least threshold bytes.
+// Only enable it for servers that accept Content-Encoding: gzip.
+func WithRequestCompression(threshold int) Option {
+	return func(c *Client) {
+		c.compressRequests = true
+		c.compressionThreshold = threshold
+	}
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// decodedBody returns a reader of body with the content encoding in header
+// removed. Closing the response body remains the caller's responsibility.
+func decodedBody(header http.Header, body io.Reader) (io.Reader, error) {
+	if !strings.EqualFold(strings.TrimSpace(header.Get("Content-Encoding")), "gzip") {
+		return body, nil
+	}
+
+	return gzip.NewReader(body)
+}
+
+// countingReader counts the bytes read from reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+
+	return n, err
+}
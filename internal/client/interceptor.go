@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+)
+
+// Call describes one attempt of an API operation as seen by interceptors.
+type Call struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Endpoint is the API path relative to the base URL, such as "diff/".
+	Endpoint string
+
+	// Request is the value encoded as the JSON request body: models.Request for
+	// synchronization, and models.Transaction or []models.Transaction for
+	// suggestions. Interceptors may replace it before calling the next handler.
+	Request any
+
+	// Response points to the value the response body is decoded into, such as
+	// *models.Response. It is populated when the next handler returns nil. An
+	// interceptor that does not call the next handler may fill it to answer the
+	// call without a request.
+	Response any
+
+	// Attempt is the 1-based attempt number and MaxAttempts the number of
+	// attempts allowed by the retry policy. Operation interceptors see zero
+	// before calling the next handler and the number of attempts made after it
+	// returns.
+	Attempt     int
+	MaxAttempts int
+
+	// StatusCode, RequestID and ResponseSize describe the HTTP response of the
+	// attempt, or of the last attempt for operation interceptors. They are
+	// zero when no response was received. ResponseSize counts the body bytes
+	// received on the wire, so it is the compressed size of gzip responses.
+	StatusCode   int
+	RequestID    string
+	ResponseSize int64
+
+	// retryable is set by the transport when the attempt failed before a
+	// response was received.
+	retryable bool
+}
+
+// Handler performs a call. Errors returned by the SDK handler are
+// *errors.Error values.
+type Handler func(ctx context.Context, call *Call) error
+
+// Interceptor wraps every attempt of an API operation. It may inspect or
+// replace call.Request, call next zero or more times, and inspect the decoded
+// call.Response or the returned error. Errors that are not *errors.Error are
+// wrapped with ErrInvalidRequest before they reach the caller.
+type Interceptor func(ctx context.Context, call *Call, next Handler) error
+
+// WithInterceptors appends interceptors to the chain. The first interceptor
+// is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithOperationInterceptors appends interceptors that run once per operation,
+// around all attempts and retry waits. The first interceptor is the outermost
+// one.
+func WithOperationInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.operationInterceptors = append(c.operationInterceptors, interceptors...)
+	}
+}
+
+// chain returns a Handler that runs interceptors around handler.
+func chain(interceptors []Interceptor, handler Handler) Handler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+
+	return handler
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+)
+
+// Metrics receives measurements of HTTP attempts. Implementations must be
+// safe for concurrent use and should return quickly.
+type Metrics interface {
+	// RequestCompleted is called once per HTTP attempt. outcome is "success",
+	// "error", or "context_ended", and code is empty for successful attempts.
+	// Attempts rejected by the circuit breaker are reported as errors with
+	// ErrCircuitOpen and a zero duration.
+	RequestCompleted(endpoint string, outcome string, code errors.ErrorCode, duration time.Duration)
+
+	// BytesReceived reports the size of a successful response body as
+	// received on the wire, before decompression.
+	BytesReceived(endpoint string, bytes int64)
+
+	// RetryScheduled is called before the client waits to retry a failed
+	// attempt.
+	RetryScheduled(endpoint string, wait time.Duration)
+
+	// RateLimited is called when the server responds with 429 Too Many
+	// Requests. retryAfter is the delay requested in the Retry-After header,
+	// or zero when the header is missing.
+	RateLimited(endpoint string, retryAfter time.Duration)
+}
+
+// NopMetrics discards all measurements.
+type NopMetrics struct{}
+
+func (NopMetrics) RequestCompleted(string, string, errors.ErrorCode, time.Duration) {}
+
+func (NopMetrics) BytesReceived(string, int64) {}
+
+func (NopMetrics) RetryScheduled(string, time.Duration) {}
+
+func (NopMetrics) RateLimited(string, time.Duration) {}
+
+// WithMetrics sets the metrics collector. A nil collector disables metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// retryAfter parses the Retry-After header given in seconds or as an HTTP
+// date. Missing, malformed, and past values yield zero.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,69 @@
+// Package finance holds the calculations shared by the analysis packages:
+// which transactions are expenses, payee normalization, calendar arithmetic on
+// ZenMoney intervals, and rounding of amounts.
+package finance
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// IsExpense reports whether transaction is an expense: it is not deleted, has
+// a positive outcome, and takes money from and to the same account.
+// Transfers, income, and debt operations are not expenses.
+func IsExpense(transaction models.Transaction) bool {
+	return !transaction.Deleted &&
+		transaction.Outcome > 0 &&
+		transaction.OutcomeAccount != nil &&
+		*transaction.OutcomeAccount == transaction.IncomeAccount
+}
+
+// AddInterval adds n intervals to date, keeping its time of day. Months and
+// years are added calendar-wise, clamping the day to the end of shorter
+// months.
+func AddInterval(date time.Time, interval models.Interval, n int) time.Time {
+	switch interval {
+	case models.IntervalDay:
+		return date.AddDate(0, 0, n)
+	case models.IntervalWeek:
+		return date.AddDate(0, 0, 7*n)
+	case models.IntervalYear:
+		n *= 12
+	}
+
+	day := MonthDay(date.Year(), date.Month()+time.Month(n), date.Day(), date.Location())
+	hour, minute, second := date.Clock()
+
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, date.Nanosecond(), date.Location())
+}
+
+// MonthDay returns midnight of day in the month, clamped to the last day of
+// the month. Months outside January to December are normalized as in
+// time.Date.
+func MonthDay(year int, month time.Month, day int, location *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, location)
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	return first.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// Round rounds value to cents.
+func Round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// Median returns the median of values, which must not be empty. values is
+// not modified.
+func Median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
@@ -0,0 +1,48 @@
+package finance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExpense(t *testing.T) {
+	card, savings := "card", "savings"
+
+	require.True(t, finance.IsExpense(models.Transaction{IncomeAccount: card, OutcomeAccount: &card, Outcome: 10}))
+	require.False(t, finance.IsExpense(models.Transaction{IncomeAccount: card, OutcomeAccount: &card, Income: 10}))
+	require.False(t, finance.IsExpense(models.Transaction{IncomeAccount: savings, OutcomeAccount: &card, Outcome: 10}))
+	require.False(t, finance.IsExpense(models.Transaction{IncomeAccount: card, OutcomeAccount: &card, Outcome: 10, Deleted: true}))
+	require.False(t, finance.IsExpense(models.Transaction{IncomeAccount: card, Outcome: 10}))
+}
+
+func TestAddInterval(t *testing.T) {
+	date := time.Date(2024, time.January, 31, 15, 0, 0, 0, time.UTC)
+
+	require.Equal(t, time.Date(2024, time.February, 1, 15, 0, 0, 0, time.UTC), finance.AddInterval(date, models.IntervalDay, 1))
+	require.Equal(t, time.Date(2024, time.February, 14, 15, 0, 0, 0, time.UTC), finance.AddInterval(date, models.IntervalWeek, 2))
+	require.Equal(t, time.Date(2024, time.February, 29, 15, 0, 0, 0, time.UTC), finance.AddInterval(date, models.IntervalMonth, 1))
+	require.Equal(t, time.Date(2023, time.November, 30, 15, 0, 0, 0, time.UTC), finance.AddInterval(date, models.IntervalMonth, -2))
+	require.Equal(t, time.Date(2025, time.January, 31, 15, 0, 0, 0, time.UTC), finance.AddInterval(date, models.IntervalYear, 1))
+	require.Equal(t, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC),
+		finance.AddInterval(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), models.IntervalYear, 1))
+}
+
+func TestNormalizePayee(t *testing.T) {
+	require.Equal(t, "coffee house 12", finance.NormalizePayee("  Coffee-House #12 "))
+	require.Equal(t, "пятёрочка", finance.NormalizePayee("ПЯТЁРОЧКА!"))
+	require.Empty(t, finance.NormalizePayee("*** "))
+}
+
+func TestRoundAndMedian(t *testing.T) {
+	values := []float64{3, 1, 2, 10}
+
+	require.Equal(t, 2.5, finance.Median(values))
+	require.Equal(t, []float64{3, 1, 2, 10}, values)
+	require.Equal(t, 2.0, finance.Median(values[:3]))
+	require.Equal(t, 1.24, finance.Round(1.235))
+	require.Equal(t, -0.33, finance.Round(-1.0/3))
+}
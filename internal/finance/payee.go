@@ -0,0 +1,17 @@
+package finance
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizePayee lowercases payee and reduces it to letters and digits
+// separated by single spaces, so bank-specific punctuation and spacing do not
+// affect comparisons.
+func NormalizePayee(payee string) string {
+	fields := strings.FieldsFunc(strings.ToLower(payee), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,88 @@
+// Package txtest builds transactions for the tests of the analysis packages.
+//
+//	transaction := txtest.Expense("t1", "2024-01-09", 100, txtest.Payee("Cafe"), txtest.Tags("food"))
+package txtest
+
+import "github.com/nemirlev/zenmoney-go-sdk/v3/models"
+
+// Account is the account that Expense takes money from by default.
+const Account = "card"
+
+// Instrument is the instrument of Expense amounts by default.
+const Instrument = 1
+
+// Option adjusts a transaction built by Expense.
+type Option func(*models.Transaction)
+
+// Expense returns an expense of amount taken from Account in Instrument on
+// date, adjusted by opts.
+func Expense(id string, date string, amount float64, opts ...Option) models.Transaction {
+	account := Account
+	transaction := models.Transaction{
+		ID:                id,
+		Date:              date,
+		IncomeAccount:     account,
+		OutcomeAccount:    &account,
+		Outcome:           amount,
+		IncomeInstrument:  Instrument,
+		OutcomeInstrument: Instrument,
+	}
+	for _, opt := range opts {
+		opt(&transaction)
+	}
+
+	return transaction
+}
+
+// FromAccount takes the expense from account instead of Account.
+func FromAccount(account string) Option {
+	return func(t *models.Transaction) {
+		t.IncomeAccount = account
+		t.OutcomeAccount = &account
+	}
+}
+
+// InInstrument records the expense in instrument instead of Instrument.
+func InInstrument(instrument int) Option {
+	return func(t *models.Transaction) {
+		t.IncomeInstrument = instrument
+		t.OutcomeInstrument = instrument
+	}
+}
+
+// Payee sets the payee.
+func Payee(payee string) Option {
+	return func(t *models.Transaction) {
+		t.Payee = payee
+	}
+}
+
+// OriginalPayee sets the payee as reported by the bank.
+func OriginalPayee(payee string) Option {
+	return func(t *models.Transaction) {
+		t.OriginalPayee = payee
+	}
+}
+
+// Tags sets the tags.
+func Tags(ids ...string) Option {
+	return func(t *models.Transaction) {
+		t.Tag = ids
+	}
+}
+
+// Paid records that the expense was paid as amount in instrument.
+func Paid(amount float64, instrument int) Option {
+	return func(t *models.Transaction) {
+		t.OpOutcome = amount
+		t.OpOutcomeInstrument = &instrument
+	}
+}
+
+// At sets the location.
+func At(lat float64, lon float64) Option {
+	return func(t *models.Transaction) {
+		t.Latitude = &lat
+		t.Longitude = &lon
+	}
+}
@@ -0,0 +1,40 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBookOpensLoansAsLiabilities(t *testing.T) {
+	rub := int32(2)
+	startBalance, balance := 1200.0, -1000.0
+	cardStart, cardBalance := 500.0, 300.0
+	card := "card"
+
+	b, err := buildBook(models.Response{
+		Instrument: []models.Instrument{{ID: 2, ShortTitle: "RUB"}},
+		Account: []models.Account{
+			{ID: "loan", Type: "loan", Title: "Mortgage", Instrument: &rub, StartBalance: &startBalance, Balance: &balance},
+			{ID: "card", Type: "ccard", Title: "Card", Instrument: &rub, StartBalance: &cardStart, Balance: &cardBalance},
+		},
+		Transaction: []models.Transaction{{
+			ID: "repayment", Date: "2024-03-01", IncomeAccount: "loan", OutcomeAccount: &card,
+			Income: 200, Outcome: 200, IncomeInstrument: 2, OutcomeInstrument: 2,
+		}},
+	}, Options{Format: FormatHLedger})
+	require.NoError(t, err)
+
+	totals := make(map[string]float64)
+	for _, e := range b.entries {
+		for _, p := range e.postings {
+			totals[p.account] += p.amount
+		}
+	}
+	require.Len(t, b.assertions, 2)
+	for _, a := range b.assertions {
+		require.Equal(t, a.amount, totals[a.account], a.account)
+	}
+	require.Equal(t, -1000.0, totals["Liabilities:Loans:Mortgage"])
+}
@@ -0,0 +1,322 @@
+// Package journal exports ZenMoney snapshots as plain-text accounting journals.
+//
+// Write renders a synchronization response in ledger, hledger, or beancount
+// syntax. Accounts are named from Account.Type and Account.Title, categories
+// follow the Tag hierarchy, transfers become balanced two-posting entries, and
+// current account balances are emitted as balance assertions.
+package journal
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Format identifies a plain-text accounting journal dialect.
+type Format string
+
+const (
+	FormatLedger    Format = "ledger"
+	FormatHLedger   Format = "hledger"
+	FormatBeancount Format = "beancount"
+)
+
+// Options controls how a snapshot is rendered.
+type Options struct {
+	// Format selects the journal dialect. It defaults to FormatLedger.
+	Format Format
+
+	// AsOf is the date at which Account.Balance values are asserted. It
+	// defaults to the date of the latest exported transaction or, when there
+	// are none, the snapshot's server timestamp.
+	AsOf time.Time
+
+	// SkipBalanceAssertions disables balance assertions derived from
+	// Account.Balance.
+	SkipBalanceAssertions bool
+
+	// SkipOpeningBalances disables entries derived from Account.StartBalance.
+	SkipOpeningBalances bool
+}
+
+// Write renders snapshot to w using opts. Deleted transactions are skipped.
+// Write returns an *errors.Error with ErrInvalidRequest when opts contains an
+// unsupported format or when a transaction cannot be converted into a balanced
+// entry. Errors from w are returned wrapped.
+func Write(w io.Writer, snapshot models.Response, opts Options) error {
+	if opts.Format == "" {
+		opts.Format = FormatLedger
+	}
+	if !slices.Contains([]Format{FormatLedger, FormatHLedger, FormatBeancount}, opts.Format) {
+		return errors.New(errors.ErrInvalidRequest, fmt.Sprintf("unsupported journal format %q", opts.Format), nil)
+	}
+
+	book, err := buildBook(snapshot, opts)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	renderBook(out, book, opts.Format)
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return nil
+}
+
+// book is the dialect-independent representation of a journal.
+type book struct {
+	opens      []open
+	entries    []entry
+	assertions []assertion
+}
+
+type open struct {
+	date      string
+	account   string
+	commodity string
+}
+
+type entry struct {
+	date      string
+	pending   bool
+	payee     string
+	narration string
+	id        string
+	postings  []posting
+}
+
+type posting struct {
+	account   string
+	amount    float64
+	commodity string
+
+	// costAmount and costCommodity hold the total price of the posting when
+	// the operation currency differs from the account currency.
+	costAmount    float64
+	costCommodity string
+}
+
+type assertion struct {
+	date      string
+	account   string
+	amount    float64
+	commodity string
+}
+
+func buildBook(snapshot models.Response, opts Options) (book, error) {
+	names := newNamer(snapshot, opts.Format)
+
+	transactions := make([]models.Transaction, 0, len(snapshot.Transaction))
+	for _, transaction := range snapshot.Transaction {
+		if !transaction.Deleted {
+			transactions = append(transactions, transaction)
+		}
+	}
+	slices.SortStableFunc(transactions, func(a, b models.Transaction) int {
+		return cmp.Or(
+			cmp.Compare(a.Date, b.Date),
+			cmp.Compare(a.Created, b.Created),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	var result book
+	for _, transaction := range transactions {
+		converted, err := convertTransaction(transaction, names)
+		if err != nil {
+			return book{}, err
+		}
+		result.entries = append(result.entries, converted)
+	}
+
+	asOf := opts.AsOf
+	if asOf.IsZero() {
+		asOf = time.Unix(snapshot.ServerTimestamp, 0).UTC()
+		if len(transactions) > 0 {
+			if latest, err := time.Parse(time.DateOnly, transactions[len(transactions)-1].Date); err == nil {
+				asOf = latest
+			}
+		}
+	}
+	firstDate := asOf.Format(time.DateOnly)
+	if len(transactions) > 0 && transactions[0].Date < firstDate {
+		firstDate = transactions[0].Date
+	}
+
+	var openings []entry
+	for _, account := range snapshot.Account {
+		name := names.account(account.ID)
+		commodity := names.commodity(instrumentID(account.Instrument))
+		result.opens = append(result.opens, open{date: firstDate, account: name, commodity: commodity})
+
+		if !opts.SkipOpeningBalances && account.StartBalance != nil && *account.StartBalance != 0 {
+			amount := *account.StartBalance
+			if account.AccountType() == models.AccountTypeLoan {
+				// The start balance of a loan is the borrowed principal, which
+				// the account owes, while its balance is negative.
+				amount = -math.Abs(amount)
+			}
+			openings = append(openings, entry{
+				date:  firstDate,
+				payee: "Opening balance",
+				id:    account.ID,
+				postings: []posting{
+					{account: name, amount: amount, commodity: commodity},
+					{account: openingBalancesAccount, amount: -amount, commodity: commodity},
+				},
+			})
+		}
+		if !opts.SkipBalanceAssertions && account.Balance != nil {
+			result.assertions = append(result.assertions, assertion{
+				date:      asOf.Format(time.DateOnly),
+				account:   name,
+				amount:    *account.Balance,
+				commodity: commodity,
+			})
+		}
+	}
+	result.entries = append(openings, result.entries...)
+
+	// Categories, equity, and accounts missing from the snapshot are opened
+	// without a commodity constraint.
+	opened := make(map[string]bool, len(result.opens))
+	for _, o := range result.opens {
+		opened[o.account] = true
+	}
+	var implicit []string
+	for _, e := range result.entries {
+		for _, p := range e.postings {
+			if !opened[p.account] {
+				opened[p.account] = true
+				implicit = append(implicit, p.account)
+			}
+		}
+	}
+	slices.Sort(implicit)
+	for _, name := range implicit {
+		result.opens = append(result.opens, open{date: firstDate, account: name})
+	}
+
+	return result, nil
+}
+
+func convertTransaction(transaction models.Transaction, names *namer) (entry, error) {
+	incomeAccount := transaction.IncomeAccount
+	outcomeAccount := incomeAccount
+	if transaction.OutcomeAccount != nil && *transaction.OutcomeAccount != "" {
+		outcomeAccount = *transaction.OutcomeAccount
+	}
+	if incomeAccount == "" {
+		incomeAccount = outcomeAccount
+	}
+
+	result := entry{
+		date:     transaction.Date,
+		pending:  transaction.Hold,
+		payee:    transaction.Payee,
+		id:       transaction.ID,
+		postings: make([]posting, 0, 4),
+	}
+	if result.payee == "" {
+		result.payee = transaction.OriginalPayee
+	}
+	if transaction.Comment != nil {
+		result.narration = *transaction.Comment
+	}
+
+	incomeCommodity := names.commodity(transaction.IncomeInstrument)
+	outcomeCommodity := names.commodity(transaction.OutcomeInstrument)
+
+	if incomeAccount != outcomeAccount {
+		from := posting{
+			account:   names.account(outcomeAccount),
+			amount:    -transaction.Outcome,
+			commodity: outcomeCommodity,
+		}
+		to := posting{
+			account:   names.account(incomeAccount),
+			amount:    transaction.Income,
+			commodity: incomeCommodity,
+		}
+		if incomeCommodity != outcomeCommodity {
+			to.costAmount = transaction.Outcome
+			to.costCommodity = outcomeCommodity
+		}
+		result.postings = append(result.postings, from, to)
+
+		return result, nil
+	}
+
+	account := names.account(incomeAccount)
+	if transaction.Outcome > 0 {
+		result.postings = append(result.postings,
+			posting{account: account, amount: -transaction.Outcome, commodity: outcomeCommodity},
+			operationPosting(
+				names.category(transaction.Tag, false),
+				transaction.Outcome, outcomeCommodity,
+				transaction.OpOutcome, transaction.OpOutcomeInstrument,
+				names,
+			),
+		)
+	}
+	if transaction.Income > 0 {
+		counter := operationPosting(
+			names.category(transaction.Tag, true),
+			transaction.Income, incomeCommodity,
+			transaction.OpIncome, transaction.OpIncomeInstrument,
+			names,
+		)
+		counter.amount = -counter.amount
+		result.postings = append(result.postings,
+			posting{account: account, amount: transaction.Income, commodity: incomeCommodity},
+			counter,
+		)
+	}
+	if len(result.postings) == 0 {
+		return entry{}, errors.New(
+			errors.ErrInvalidRequest,
+			fmt.Sprintf("transaction %s has neither income nor outcome", transaction.ID),
+			nil,
+		)
+	}
+
+	return result, nil
+}
+
+// operationPosting returns the category side of an income or expense. When the
+// operation was made in a different currency, the posting is expressed in that
+// currency and priced at the amount charged to the account.
+func operationPosting(category string, amount float64, commodity string, opAmount float64, opInstrument *int, names *namer) posting {
+	result := posting{account: category, amount: amount, commodity: commodity}
+	if opAmount == 0 || opInstrument == nil {
+		return result
+	}
+
+	opCommodity := names.commodity(*opInstrument)
+	if opCommodity == commodity {
+		return result
+	}
+	result.amount = opAmount
+	result.commodity = opCommodity
+	result.costAmount = amount
+	result.costCommodity = commodity
+
+	return result
+}
+
+func instrumentID(id *int32) int {
+	if id == nil {
+		return 0
+	}
+
+	return int(*id)
+}
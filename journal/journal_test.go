@@ -0,0 +1,166 @@
+package journal_test
+
+import (
+	"bytes"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/journal"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+func testSnapshot() models.Response {
+	rub := int32(2)
+	usd := int32(1)
+	eur := 3
+	parent := "tag-food"
+	comment := "Lunch with team"
+	cardOut := "card"
+	walletOut := "wallet"
+
+	return models.Response{
+		ServerTimestamp: time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC).Unix(),
+		Instrument: []models.Instrument{
+			{ID: 1, ShortTitle: "USD"},
+			{ID: 2, ShortTitle: "RUB"},
+			{ID: 3, ShortTitle: "EUR"},
+		},
+		Account: []models.Account{
+			{ID: "card", Type: "ccard", Title: "Main: Card", Instrument: &rub, StartBalance: float64Ptr(1000), Balance: float64Ptr(5500)},
+			{ID: "wallet", Type: "cash", Title: "Wallet", Instrument: &usd, StartBalance: float64Ptr(0), Balance: float64Ptr(10)},
+		},
+		Tag: []models.Tag{
+			{ID: "tag-food", Title: "Food"},
+			{ID: "tag-cafe", Title: "Cafe", Parent: &parent},
+			{ID: "tag-salary", Title: "Salary"},
+		},
+		Transaction: []models.Transaction{
+			{
+				ID: "salary", Date: "2024-03-01", IncomeAccount: "card", OutcomeAccount: &cardOut,
+				Income: 10000, IncomeInstrument: 2, OutcomeInstrument: 2, Tag: []string{"tag-salary"}, Payee: "Employer",
+			},
+			{
+				ID: "lunch", Date: "2024-03-02", IncomeAccount: "card", OutcomeAccount: &cardOut,
+				Outcome: 500, OutcomeInstrument: 2, IncomeInstrument: 2, OpOutcome: 5, OpOutcomeInstrument: &eur,
+				Tag: []string{"tag-cafe"}, Payee: "Bistro", Comment: &comment, Hold: true,
+			},
+			{
+				ID: "exchange", Date: "2024-03-03", IncomeAccount: "wallet", OutcomeAccount: &cardOut,
+				Outcome: 5000, OutcomeInstrument: 2, Income: 50, IncomeInstrument: 1,
+			},
+			{
+				ID: "cash-spend", Date: "2024-03-04", IncomeAccount: "wallet", OutcomeAccount: &walletOut,
+				Outcome: 40, OutcomeInstrument: 1, IncomeInstrument: 1,
+			},
+			{
+				ID: "removed", Date: "2024-03-05", IncomeAccount: "card", Outcome: 1, Deleted: true,
+			},
+		},
+	}
+}
+
+func TestWriteLedger(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, journal.Write(&out, testSnapshot(), journal.Options{Format: journal.FormatLedger}))
+
+	got := out.String()
+	require.Contains(t, got, "2024/03/01 * Employer\n    ; zenmoney-id: salary\n"+
+		"    Assets:Cards:Main Card  10000.00 RUB\n"+
+		"    Income:Salary  -10000.00 RUB\n")
+	require.Contains(t, got, "2024/03/02 ! Bistro\n    ; Lunch with team\n    ; zenmoney-id: lunch\n"+
+		"    Assets:Cards:Main Card  -500.00 RUB\n"+
+		"    Expenses:Food:Cafe  5.00 EUR @@ 500.00 RUB\n")
+	require.Contains(t, got, "    Assets:Cards:Main Card  -5000.00 RUB\n"+
+		"    Assets:Cash:Wallet  50.00 USD @@ 5000.00 RUB\n")
+	require.Contains(t, got, "    Expenses:Uncategorized  40.00 USD\n")
+	require.Contains(t, got, "    Equity:Opening-Balances  -1000.00 RUB\n")
+	require.Contains(t, got, "2024/03/04 * Balance assertion\n    Assets:Cards:Main Card  0 RUB = 5500.00 RUB\n")
+	require.NotContains(t, got, "removed")
+	require.NotContains(t, got, " open ")
+}
+
+func TestWriteHLedger(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, journal.Write(&out, testSnapshot(), journal.Options{
+		Format: journal.FormatHLedger,
+		AsOf:   time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+	}))
+
+	got := out.String()
+	require.Contains(t, got, "2024-03-02 ! Bistro | Lunch with team\n    ; zenmoney-id: lunch\n")
+	require.Contains(t, got, "2024-03-10 * Balance assertion\n    Assets:Cash:Wallet  0 USD = 10.00 USD\n")
+}
+
+func TestWriteBeancount(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, journal.Write(&out, testSnapshot(), journal.Options{Format: journal.FormatBeancount}))
+
+	got := out.String()
+	require.Contains(t, got, "2024-03-01 open Assets:Cards:Main-Card RUB\n")
+	require.Contains(t, got, "2024-03-01 open Expenses:Food:Cafe\n")
+	require.Contains(t, got, "2024-03-01 open Equity:Opening-Balances\n")
+	require.Contains(t, got, `2024-03-02 ! "Bistro" "Lunch with team"`+"\n"+
+		`  zenmoney-id: "lunch"`+"\n"+
+		"    Assets:Cards:Main-Card  -500.00 RUB\n"+
+		"    Expenses:Food:Cafe  5.00 EUR @@ 500.00 RUB\n")
+	require.Contains(t, got, "2024-03-05 balance Assets:Cash:Wallet  10.00 USD\n")
+}
+
+func TestWriteOptions(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, journal.Write(&out, testSnapshot(), journal.Options{
+		SkipBalanceAssertions: true,
+		SkipOpeningBalances:   true,
+	}))
+
+	require.NotContains(t, out.String(), "Balance assertion")
+	require.NotContains(t, out.String(), "Opening-Balances")
+}
+
+func TestWriteRejectsUnsupportedFormat(t *testing.T) {
+	err := journal.Write(&bytes.Buffer{}, models.Response{}, journal.Options{Format: "gnucash"})
+
+	var sdkErr *sdkerrors.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, sdkerrors.ErrInvalidRequest, sdkErr.Code)
+}
+
+var errDiskFull = stdErrors.New("disk full")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errDiskFull
+}
+
+func TestWriteReportsWriterFailure(t *testing.T) {
+	err := journal.Write(failingWriter{}, testSnapshot(), journal.Options{})
+
+	require.ErrorIs(t, err, errDiskFull)
+}
+
+func TestWriteDisambiguatesAccountTitles(t *testing.T) {
+	var out bytes.Buffer
+	snapshot := models.Response{
+		Account: []models.Account{
+			{ID: "aaaaaaaa-1", Type: "cash", Title: "Wallet"},
+			{ID: "bbbbbbbb-2", Type: "cash", Title: "Wallet"},
+		},
+	}
+
+	require.NoError(t, journal.Write(&out, snapshot, journal.Options{Format: journal.FormatBeancount}))
+
+	require.Contains(t, out.String(), "open Assets:Cash:Wallet ZM0\n")
+	require.Contains(t, out.String(), "open Assets:Cash:Wallet-Bbbbbbbb ZM0\n")
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
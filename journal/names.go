@@ -0,0 +1,173 @@
+package journal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+const openingBalancesAccount = "Equity:Opening-Balances"
+
+// accountRoots maps ZenMoney account types to journal account prefixes.
+var accountRoots = map[string]string{
+	"cash":     "Assets:Cash",
+	"ccard":    "Assets:Cards",
+	"checking": "Assets:Checking",
+	"deposit":  "Assets:Deposits",
+	"loan":     "Liabilities:Loans",
+	"debt":     "Assets:Debts",
+}
+
+// namer resolves ZenMoney identifiers to journal account and commodity names.
+type namer struct {
+	format      Format
+	accounts    map[string]string
+	commodities map[int]string
+	tags        map[string]models.Tag
+}
+
+func newNamer(snapshot models.Response, format Format) *namer {
+	n := &namer{
+		format:      format,
+		accounts:    make(map[string]string, len(snapshot.Account)),
+		commodities: make(map[int]string, len(snapshot.Instrument)),
+		tags:        make(map[string]models.Tag, len(snapshot.Tag)),
+	}
+	for _, instrument := range snapshot.Instrument {
+		n.commodities[instrument.ID] = n.commodityName(instrument.ShortTitle, instrument.ID)
+	}
+	for _, tag := range snapshot.Tag {
+		n.tags[tag.ID] = tag
+	}
+
+	taken := make(map[string]int, len(snapshot.Account))
+	for _, account := range snapshot.Account {
+		root, ok := accountRoots[account.Type]
+		if !ok {
+			root = "Assets:Other"
+		}
+		name := root + ":" + n.component(account.Title, "Account")
+		taken[name]++
+		if taken[name] > 1 {
+			name += "-" + n.component(shortID(account.ID), "Account")
+		}
+		n.accounts[account.ID] = name
+	}
+
+	return n
+}
+
+func (n *namer) account(id string) string {
+	if name, ok := n.accounts[id]; ok {
+		return name
+	}
+
+	name := "Assets:Unknown:" + n.component(shortID(id), "Account")
+	n.accounts[id] = name
+
+	return name
+}
+
+func (n *namer) commodity(id int) string {
+	if name, ok := n.commodities[id]; ok {
+		return name
+	}
+
+	return n.commodityName("", id)
+}
+
+func (n *namer) commodityName(shortTitle string, id int) string {
+	var builder strings.Builder
+	for _, r := range strings.ToUpper(shortTitle) {
+		if r >= 'A' && r <= 'Z' || builder.Len() > 0 && (r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			builder.WriteRune(r)
+		}
+	}
+	if builder.Len() == 0 {
+		return fmt.Sprintf("ZM%d", id)
+	}
+
+	return builder.String()
+}
+
+// category returns the income or expense account for the first tag of a
+// transaction, nesting child tags under their parent.
+func (n *namer) category(tagIDs []string, income bool) string {
+	root := "Expenses"
+	if income {
+		root = "Income"
+	}
+
+	name := root + ":Uncategorized"
+	if len(tagIDs) > 0 {
+		if tag, ok := n.tags[tagIDs[0]]; ok {
+			name = root + ":" + n.component(tag.Title, "Category")
+			if tag.Parent != nil {
+				if parent, ok := n.tags[*tag.Parent]; ok {
+					name = root + ":" + n.component(parent.Title, "Category") + ":" + n.component(tag.Title, "Category")
+				}
+			}
+		}
+	}
+
+	return name
+}
+
+// component converts title into a single account name component that is
+// valid in the configured dialect.
+func (n *namer) component(title string, fallback string) string {
+	if n.format == FormatBeancount {
+		return beancountComponent(title, fallback)
+	}
+
+	title = strings.ReplaceAll(title, ":", " ")
+	title = strings.Join(strings.Fields(title), " ")
+	if title == "" {
+		return fallback
+	}
+
+	return title
+}
+
+// beancountComponent keeps letters, digits, and dashes, and makes sure the
+// component starts with an upper-case letter as beancount requires.
+func beancountComponent(title string, fallback string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			dash = false
+			builder.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+
+	result := builder.String()
+	if result == "" {
+		return fallback
+	}
+	first, size := utf8.DecodeRuneInString(result)
+	switch {
+	case unicode.IsDigit(first):
+		result = "X" + result
+	case unicode.IsLower(first):
+		result = string(unicode.ToUpper(first)) + result[size:]
+	}
+
+	return result
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+
+	return id
+}
@@ -0,0 +1,121 @@
+package journal
+
+import (
+	"bufio"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func renderBook(out *bufio.Writer, b book, format Format) {
+	if format == FormatBeancount {
+		for _, o := range b.opens {
+			out.WriteString(o.date + " open " + o.account)
+			if o.commodity != "" {
+				out.WriteString(" " + o.commodity)
+			}
+			out.WriteByte('\n')
+		}
+		if len(b.opens) > 0 {
+			out.WriteByte('\n')
+		}
+	}
+
+	for _, e := range b.entries {
+		renderEntry(out, e, format)
+		out.WriteByte('\n')
+	}
+
+	for _, a := range b.assertions {
+		renderAssertion(out, a, format)
+	}
+}
+
+func renderEntry(out *bufio.Writer, e entry, format Format) {
+	flag := "*"
+	if e.pending {
+		flag = "!"
+	}
+
+	switch format {
+	case FormatBeancount:
+		out.WriteString(e.date + " " + flag + " " + quote(e.payee) + " " + quote(e.narration) + "\n")
+		out.WriteString("  zenmoney-id: " + quote(e.id) + "\n")
+	case FormatHLedger:
+		out.WriteString(e.date + " " + flag + " " + singleLine(e.payee))
+		if e.narration != "" {
+			out.WriteString(" | " + singleLine(e.narration))
+		}
+		out.WriteString("\n    ; zenmoney-id: " + e.id + "\n")
+	default:
+		out.WriteString(strings.ReplaceAll(e.date, "-", "/") + " " + flag + " " + singleLine(e.payee) + "\n")
+		if e.narration != "" {
+			out.WriteString("    ; " + singleLine(e.narration) + "\n")
+		}
+		out.WriteString("    ; zenmoney-id: " + e.id + "\n")
+	}
+
+	for _, p := range e.postings {
+		out.WriteString("    " + p.account + "  " + formatAmount(p.amount) + " " + p.commodity)
+		if p.costCommodity != "" {
+			out.WriteString(" @@ " + formatAmount(math.Abs(p.costAmount)) + " " + p.costCommodity)
+		}
+		out.WriteByte('\n')
+	}
+}
+
+func renderAssertion(out *bufio.Writer, a assertion, format Format) {
+	amount := formatAmount(a.amount) + " " + a.commodity
+
+	switch format {
+	case FormatBeancount:
+		// Beancount checks balances at the beginning of the day, so the
+		// assertion is dated one day after the balance it describes.
+		out.WriteString(nextDay(a.date) + " balance " + a.account + "  " + amount + "\n")
+	case FormatHLedger:
+		out.WriteString(a.date + " * Balance assertion\n")
+		out.WriteString("    " + a.account + "  0 " + a.commodity + " = " + amount + "\n\n")
+	default:
+		out.WriteString(strings.ReplaceAll(a.date, "-", "/") + " * Balance assertion\n")
+		out.WriteString("    " + a.account + "  0 " + a.commodity + " = " + amount + "\n\n")
+	}
+}
+
+// formatAmount formats value with at least two fractional digits and without
+// floating-point noise beyond eight digits.
+func formatAmount(value float64) string {
+	value = math.Round(value*1e8) / 1e8
+	if value == 0 {
+		// Normalize negative zero.
+		value = 0
+	}
+
+	result := strconv.FormatFloat(value, 'f', -1, 64)
+	dot := strings.IndexByte(result, '.')
+	switch {
+	case dot < 0:
+		result += ".00"
+	case len(result)-dot-1 < 2:
+		result += "0"
+	}
+
+	return result
+}
+
+func quote(value string) string {
+	return strconv.Quote(singleLine(value))
+}
+
+func singleLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func nextDay(date string) string {
+	parsed, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+
+	return parsed.AddDate(0, 0, 1).Format(time.DateOnly)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Clock provides the current time. Implementations must be safe for
+// concurrent use. The API client, the constructors of new entities, and the
+// file store accept the same Clock, so one fake clock controls every
+// timestamp the SDK writes.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the time from the operating system.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
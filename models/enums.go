@@ -0,0 +1,155 @@
+package models
+
+//
+// Enumerated string values of entity fields. The fields themselves are plain
+// strings, so values unknown to this SDK are decoded and encoded unchanged;
+// use the typed accessors below and IsValid to detect them.
+//
+
+// AccountType is the type of an account.
+type AccountType string
+
+const (
+	AccountTypeCash       AccountType = "cash"
+	AccountTypeCreditCard AccountType = "ccard"
+	AccountTypeChecking   AccountType = "checking"
+	AccountTypeLoan       AccountType = "loan"
+	AccountTypeDeposit    AccountType = "deposit"
+	AccountTypeEMoney     AccountType = "emoney"
+	AccountTypeDebt       AccountType = "debt"
+)
+
+// IsValid reports whether t is one of the AccountType constants.
+func (t AccountType) IsValid() bool {
+	switch t {
+	case AccountTypeCash, AccountTypeCreditCard, AccountTypeChecking, AccountTypeLoan,
+		AccountTypeDeposit, AccountTypeEMoney, AccountTypeDebt:
+		return true
+	}
+
+	return false
+}
+
+// ReminderMarkerState is the state of a planned operation.
+type ReminderMarkerState string
+
+const (
+	ReminderMarkerStatePlanned   ReminderMarkerState = "planned"
+	ReminderMarkerStateProcessed ReminderMarkerState = "processed"
+	ReminderMarkerStateDeleted   ReminderMarkerState = "deleted"
+)
+
+// IsValid reports whether s is one of the ReminderMarkerState constants.
+func (s ReminderMarkerState) IsValid() bool {
+	switch s {
+	case ReminderMarkerStatePlanned, ReminderMarkerStateProcessed, ReminderMarkerStateDeleted:
+		return true
+	}
+
+	return false
+}
+
+// Interval is a calendar unit of reminder schedules and loan and deposit
+// terms.
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week"
+	IntervalMonth Interval = "month"
+	IntervalYear  Interval = "year"
+)
+
+// IsValid reports whether i is one of the Interval constants.
+func (i Interval) IsValid() bool {
+	switch i {
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalYear:
+		return true
+	}
+
+	return false
+}
+
+// BalanceCorrectionType controls how the app corrects an account balance from
+// a bank notification.
+type BalanceCorrectionType string
+
+// BalanceCorrectionRequest is the only value given in the API documentation.
+const BalanceCorrectionRequest BalanceCorrectionType = "request"
+
+// IsValid reports whether t is BalanceCorrectionRequest.
+func (t BalanceCorrectionType) IsValid() bool {
+	switch t {
+	case BalanceCorrectionRequest:
+		return true
+	}
+
+	return false
+}
+
+// PlanBalanceMode is the way the app computes the planned balance.
+type PlanBalanceMode string
+
+const (
+	PlanBalanceModeBalance  PlanBalanceMode = "balance"
+	PlanBalanceModeCashflow PlanBalanceMode = "cashflow"
+)
+
+// IsValid reports whether m is one of the PlanBalanceMode constants.
+func (m PlanBalanceMode) IsValid() bool {
+	switch m {
+	case PlanBalanceModeBalance, PlanBalanceModeCashflow:
+		return true
+	}
+
+	return false
+}
+
+//
+// Typed accessors of the enumerated entity fields. The fields stay plain
+// strings for compatibility; the accessors return the same values as the
+// types above, and an unset interval is returned as an empty Interval.
+//
+
+// AccountType returns Type as an AccountType.
+func (a Account) AccountType() AccountType {
+	return AccountType(a.Type)
+}
+
+// CorrectionType returns BalanceCorrectionType as a BalanceCorrectionType.
+func (a Account) CorrectionType() BalanceCorrectionType {
+	return BalanceCorrectionType(a.BalanceCorrectionType)
+}
+
+// TermInterval returns EndDateOffsetInterval as an Interval.
+func (a Account) TermInterval() Interval {
+	return intervalOf(a.EndDateOffsetInterval)
+}
+
+// PaymentInterval returns PayoffInterval as an Interval.
+func (a Account) PaymentInterval() Interval {
+	return intervalOf(a.PayoffInterval)
+}
+
+// RepeatInterval returns Interval as an Interval.
+func (r Reminder) RepeatInterval() Interval {
+	return intervalOf(r.Interval)
+}
+
+// MarkerState returns State as a ReminderMarkerState.
+func (m ReminderMarker) MarkerState() ReminderMarkerState {
+	return ReminderMarkerState(m.State)
+}
+
+// BalanceMode returns PlanBalanceMode as a PlanBalanceMode.
+func (u User) BalanceMode() PlanBalanceMode {
+	return PlanBalanceMode(u.PlanBalanceMode)
+}
+
+func intervalOf(value *string) Interval {
+	if value == nil {
+		return ""
+	}
+
+	return Interval(*value)
+}
@@ -3,12 +3,15 @@ package models_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
 	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
 	"github.com/stretchr/testify/require"
 )
@@ -172,3 +175,191 @@ func TestNullableFieldsAcceptValues(t *testing.T) {
 func TestEntityTypeCountry(t *testing.T) {
 	require.Equal(t, models.EntityType("country"), models.EntityTypeCountry)
 }
+
+func TestFixtureEntitiesValidate(t *testing.T) {
+	payload, err := os.ReadFile("../example.json")
+	require.NoError(t, err)
+
+	var response models.Response
+	require.NoError(t, json.Unmarshal(payload, &response))
+
+	for _, account := range response.Account {
+		require.NoError(t, account.Validate(), account.ID)
+	}
+	for _, tag := range response.Tag {
+		require.NoError(t, tag.Validate(), tag.ID)
+	}
+	require.NoError(t, models.ValidateTags(response.Tag))
+	for _, merchant := range response.Merchant {
+		require.NoError(t, merchant.Validate(), merchant.ID)
+	}
+	for _, budget := range response.Budget {
+		require.NoError(t, budget.Validate(), budget.Date)
+	}
+	for _, reminder := range response.Reminder {
+		require.NoError(t, reminder.Validate(), reminder.ID)
+	}
+	for _, marker := range response.ReminderMarker {
+		require.NoError(t, marker.Validate(), marker.ID)
+	}
+	for _, transaction := range response.Transaction {
+		require.NoError(t, transaction.Validate(), transaction.ID)
+	}
+}
+
+func TestValidateReportsFieldPaths(t *testing.T) {
+	invalid := "not-a-uuid"
+	transaction := models.Transaction{
+		ID:                "7f1c0a4e-3c55-4f7e-9a43-2b8f5c1d9e01",
+		User:              1,
+		Date:              "2024-02-30",
+		IncomeAccount:     "7f1c0a4e-3c55-4f7e-9a43-2b8f5c1d9e02",
+		OutcomeAccount:    &invalid,
+		IncomeInstrument:  1,
+		OutcomeInstrument: 1,
+		Outcome:           -5,
+		Tag:               []string{"7f1c0a4e-3c55-4f7e-9a43-2b8f5c1d9e03", ""},
+	}
+
+	err := transaction.Validate()
+	var sdkErr *sdkerrors.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, sdkerrors.ErrInvalidRequest, sdkErr.Code)
+	var validationErr *sdkerrors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, "transaction", validationErr.Entity)
+	require.Equal(t, []string{"date", "outcomeAccount", "outcome", "tag[1]"}, fieldPaths(validationErr))
+
+	transaction.Deleted = true
+	transaction.Date = "2024-02-29"
+	require.NoError(t, transaction.Validate())
+}
+
+func TestValidateTagDepth(t *testing.T) {
+	root := "7f1c0a4e-3c55-4f7e-9a43-2b8f5c1d9e01"
+	child := "7f1c0a4e-3c55-4f7e-9a43-2b8f5c1d9e02"
+	tags := []models.Tag{
+		{ID: root, User: 1, Title: "Food"},
+		{ID: child, User: 1, Title: "Cafe", Parent: &root},
+		{ID: "7f1c0a4e-3c55-4f7e-9a43-2b8f5c1d9e03", User: 1, Title: "Coffee", Parent: &child},
+	}
+
+	var validationErr *sdkerrors.ValidationError
+	require.ErrorAs(t, models.ValidateTags(tags), &validationErr)
+	require.Equal(t, []string{"[2].parent"}, fieldPaths(validationErr))
+}
+
+func TestValidateAccountAndBudget(t *testing.T) {
+	account := models.Account{
+		ID:    "7f1c0a4e-3c55-4f7e-9a43-2b8f5c1d9e01",
+		User:  1,
+		Type:  "deposit",
+		Title: "Savings",
+	}
+	var validationErr *sdkerrors.ValidationError
+	require.ErrorAs(t, account.Validate(), &validationErr)
+	require.Equal(t, []string{
+		"instrument", "startDate", "percent", "capitalization", "endDateOffset", "endDateOffsetInterval",
+	}, fieldPaths(validationErr))
+
+	budget := models.Budget{User: 1, Date: "2024-03-15", Outcome: -1}
+	require.ErrorAs(t, budget.Validate(), &validationErr)
+	require.Equal(t, []string{"date", "outcome"}, fieldPaths(validationErr))
+}
+
+func fieldPaths(err *sdkerrors.ValidationError) []string {
+	paths := make([]string, len(err.Fields))
+	for i, field := range err.Fields {
+		paths[i] = field.Field
+	}
+
+	return paths
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestConstructorsProduceValidEntities(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+	ids := 0
+	opts := []models.NewOption{
+		models.WithClock(fixedClock(now)),
+		models.WithIDGenerator(func() string {
+			ids++
+			return fmt.Sprintf("00000000-0000-4000-8000-%012d", ids)
+		}),
+	}
+	account := models.NewAccount(1, "cash", "Wallet", 2, opts...)
+	savings := models.NewAccount(1, "checking", "Savings", 3, opts...)
+
+	expense := models.NewExpense(1, account.ID, 12.5, 2, now, opts...)
+	require.Equal(t, "00000000-0000-4000-8000-000000000003", expense.ID)
+	require.Equal(t, "2024-03-05", expense.Date)
+	require.Equal(t, now.Unix(), expense.Created)
+	require.Equal(t, now.Unix(), expense.Changed)
+	require.Equal(t, 12.5, expense.Outcome)
+	require.Equal(t, account.ID, expense.IncomeAccount)
+	require.Equal(t, account.ID, *expense.OutcomeAccount)
+
+	income := models.NewIncome(1, account.ID, 100, 2, now, opts...)
+	require.Equal(t, 100.0, income.Income)
+	require.Zero(t, income.Outcome)
+
+	transfer := models.NewTransfer(1, account.ID, 100, 2, savings.ID, 1.1, 3, now, opts...)
+	require.Equal(t, account.ID, *transfer.OutcomeAccount)
+	require.Equal(t, savings.ID, transfer.IncomeAccount)
+	require.Equal(t, 3, transfer.IncomeInstrument)
+
+	tag := models.NewTag(1, "Food", opts...)
+	merchant := models.NewMerchant(1, "Bakery", opts...)
+	reminder := models.NewReminder(1, account.ID, 30, 2, now, "month", 1, opts...)
+
+	for _, entity := range []interface{ Validate() error }{
+		account, savings, expense, income, transfer, tag, merchant, reminder,
+	} {
+		require.NoError(t, entity.Validate())
+	}
+
+	payload, err := json.Marshal(models.Request{Transaction: []models.Transaction{expense}})
+	require.NoError(t, err)
+	require.Contains(t, string(payload), `"outcomeAccount":"`+account.ID+`"`)
+}
+
+func TestNewUUID(t *testing.T) {
+	id := models.NewUUID()
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+	require.NotEqual(t, id, models.NewUUID())
+}
+
+func TestEnumsPreserveUnknownValues(t *testing.T) {
+	payload := []byte(`{"type":"crypto","title":"Wallet","balanceCorrectionType":"request","payoffInterval":"month"}`)
+
+	var account models.Account
+	require.NoError(t, json.Unmarshal(payload, &account))
+	require.Equal(t, "crypto", account.Type)
+	require.Equal(t, models.AccountType("crypto"), account.AccountType())
+	require.False(t, account.AccountType().IsValid())
+	require.Equal(t, models.BalanceCorrectionRequest, account.CorrectionType())
+	require.Equal(t, models.IntervalMonth, account.PaymentInterval())
+	require.Empty(t, account.TermInterval())
+
+	encoded, err := json.Marshal(account)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"type":"crypto"`)
+
+	require.Equal(t, models.ReminderMarkerStateDeleted, models.ReminderMarker{State: "deleted"}.MarkerState())
+	require.Equal(t, models.PlanBalanceModeBalance, models.User{PlanBalanceMode: "balance"}.BalanceMode())
+	require.Empty(t, models.Reminder{}.RepeatInterval())
+
+	require.True(t, models.AccountTypeCreditCard.IsValid())
+	require.True(t, models.ReminderMarkerStateProcessed.IsValid())
+	require.False(t, models.ReminderMarkerState("skipped").IsValid())
+	require.True(t, models.IntervalYear.IsValid())
+	require.False(t, models.Interval("").IsValid())
+	require.True(t, models.PlanBalanceModeCashflow.IsValid())
+	require.False(t, models.BalanceCorrectionType("manual").IsValid())
+	require.False(t, models.BalanceCorrectionType("auto").IsValid())
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+//
+// Constructors of new user entities ready to be sent with a synchronization request
+//
+
+// NewOption configures the constructors of new entities.
+type NewOption func(*newConfig)
+
+type newConfig struct {
+	clock Clock
+	newID func() string
+}
+
+// WithClock sets the clock that provides the Changed and Created timestamps.
+// SystemClock is used by default, and a nil clock restores it.
+func WithClock(clock Clock) NewOption {
+	return func(c *newConfig) {
+		c.clock = clock
+	}
+}
+
+// WithIDGenerator sets the function that generates entity IDs. NewUUID is
+// used by default.
+func WithIDGenerator(newID func() string) NewOption {
+	return func(c *newConfig) {
+		c.newID = newID
+	}
+}
+
+func newOptions(opts []NewOption) newConfig {
+	cfg := newConfig{clock: SystemClock{}, newID: NewUUID}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.clock == nil {
+		cfg.clock = SystemClock{}
+	}
+
+	return cfg
+}
+
+// NewUUID returns a random version 4 UUID in its lowercase canonical form.
+func NewUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// NewExpense returns a transaction that spends amount of instrument from
+// account on date.
+func NewExpense(user int, account string, amount float64, instrument int, date time.Time, opts ...NewOption) Transaction {
+	return newTransaction(user, account, 0, instrument, account, amount, instrument, date, opts)
+}
+
+// NewIncome returns a transaction that adds amount of instrument to account
+// on date.
+func NewIncome(user int, account string, amount float64, instrument int, date time.Time, opts ...NewOption) Transaction {
+	return newTransaction(user, account, amount, instrument, account, 0, instrument, date, opts)
+}
+
+// NewTransfer returns a transaction that moves outcome of outcomeInstrument
+// from one account and credits income of incomeInstrument to another. The
+// amounts differ when the accounts use different currencies.
+func NewTransfer(user int, from string, outcome float64, outcomeInstrument int, to string, income float64, incomeInstrument int, date time.Time, opts ...NewOption) Transaction {
+	return newTransaction(user, to, income, incomeInstrument, from, outcome, outcomeInstrument, date, opts)
+}
+
+func newTransaction(user int, incomeAccount string, income float64, incomeInstrument int, outcomeAccount string, outcome float64, outcomeInstrument int, date time.Time, opts []NewOption) Transaction {
+	cfg := newOptions(opts)
+	now := cfg.clock.Now().Unix()
+
+	return Transaction{
+		ID:                cfg.newID(),
+		User:              user,
+		Date:              date.Format(time.DateOnly),
+		Income:            income,
+		Outcome:           outcome,
+		Changed:           now,
+		Created:           now,
+		IncomeInstrument:  incomeInstrument,
+		OutcomeInstrument: outcomeInstrument,
+		IncomeAccount:     incomeAccount,
+		OutcomeAccount:    &outcomeAccount,
+	}
+}
+
+// NewAccount returns an account of accountType, such as "cash" or "ccard",
+// with a zero balance that is included in the total balance.
+func NewAccount(user int, accountType string, title string, instrument int, opts ...NewOption) Account {
+	cfg := newOptions(opts)
+	instrumentID := int32(instrument)
+	zero := 0.0
+	savings := false
+
+	return Account{
+		ID:                    cfg.newID(),
+		User:                  user,
+		Instrument:            &instrumentID,
+		Type:                  accountType,
+		Savings:               &savings,
+		Title:                 title,
+		InBalance:             true,
+		CreditLimit:           &zero,
+		StartBalance:          &zero,
+		Balance:               &zero,
+		BalanceCorrectionType: string(BalanceCorrectionRequest),
+		Changed:               cfg.clock.Now().Unix(),
+	}
+}
+
+// NewTag returns a top-level expense category shown in the outcome budget.
+// Set Parent to nest it under another tag.
+func NewTag(user int, title string, opts ...NewOption) Tag {
+	cfg := newOptions(opts)
+
+	return Tag{
+		ID:            cfg.newID(),
+		User:          user,
+		Changed:       cfg.clock.Now().Unix(),
+		BudgetOutcome: true,
+		Title:         title,
+		ShowOutcome:   true,
+	}
+}
+
+// NewMerchant returns a merchant without a known category code.
+func NewMerchant(user int, title string, opts ...NewOption) Merchant {
+	cfg := newOptions(opts)
+
+	return Merchant{
+		ID:      cfg.newID(),
+		User:    user,
+		Title:   title,
+		Changed: cfg.clock.Now().Unix(),
+	}
+}
+
+// NewReminder returns a reminder of an expense of amount of instrument from
+// account that repeats every step intervals, such as every 1 "month", from
+// start.
+func NewReminder(user int, account string, amount float64, instrument int, start time.Time, interval string, step int, opts ...NewOption) Reminder {
+	cfg := newOptions(opts)
+
+	return Reminder{
+		ID:                cfg.newID(),
+		User:              user,
+		Outcome:           amount,
+		Changed:           cfg.clock.Now().Unix(),
+		IncomeInstrument:  instrument,
+		OutcomeInstrument: instrument,
+		Step:              step,
+		Points:            []int{0},
+		StartDate:         start.Format(time.DateOnly),
+		Notify:            true,
+		Interval:          &interval,
+		IncomeAccount:     account,
+		OutcomeAccount:    account,
+	}
+}
@@ -0,0 +1,339 @@
+package models
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+)
+
+//
+// Client-side validation of user entities before they are sent to the server
+//
+// Validate methods return nil or an *errors.Error with ErrInvalidRequest
+// whose cause is an *errors.ValidationError listing every invalid field.
+//
+
+const maxMerchantCode = 9999
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Validate checks the fields the server requires for an account, including
+// the loan and deposit terms.
+func (a Account) Validate() error {
+	v := validator{}
+	v.uuid("id", a.ID)
+	v.positive("user", a.User)
+	if a.Instrument == nil {
+		v.add("instrument", "is required")
+	} else {
+		v.positive("instrument", int(*a.Instrument))
+	}
+	v.enum("type", a.Type, a.AccountType().IsValid())
+	v.required("title", a.Title)
+	v.optionalNonNegative("creditLimit", a.CreditLimit)
+	v.optionalDate("startDate", a.StartDate)
+	v.optionalNonNegative("percent", a.Percent)
+
+	if accountType := a.AccountType(); accountType == AccountTypeLoan || accountType == AccountTypeDeposit {
+		if a.StartDate == nil {
+			v.add("startDate", "is required for "+a.Type+" accounts")
+		}
+		if a.Percent == nil {
+			v.add("percent", "is required for "+a.Type+" accounts")
+		}
+		if a.Capitalization == nil {
+			v.add("capitalization", "is required for "+a.Type+" accounts")
+		}
+		if a.EndDateOffset == nil {
+			v.add("endDateOffset", "is required for "+a.Type+" accounts")
+		} else {
+			v.positive("endDateOffset", int(*a.EndDateOffset))
+		}
+		if a.EndDateOffsetInterval == nil {
+			v.add("endDateOffsetInterval", "is required for "+a.Type+" accounts")
+		} else {
+			v.interval("endDateOffsetInterval", a.TermInterval())
+		}
+		if a.PayoffInterval != nil {
+			v.interval("payoffInterval", a.PaymentInterval())
+		}
+		if a.PayoffStep != nil && *a.PayoffStep < 0 {
+			v.add("payoffStep", "must not be negative")
+		}
+	}
+
+	return v.err("account")
+}
+
+// Validate checks the identifiers and title of a tag. A tag must not be its
+// own parent; use ValidateTags to check the nesting depth of a set of tags.
+func (t Tag) Validate() error {
+	v := validator{}
+	v.uuid("id", t.ID)
+	v.positive("user", t.User)
+	v.required("title", t.Title)
+	if t.Parent != nil {
+		v.uuid("parent", *t.Parent)
+		if *t.Parent == t.ID {
+			v.add("parent", "must not refer to the tag itself")
+		}
+	}
+
+	return v.err("tag")
+}
+
+// ValidateTags validates every tag and checks that tags nest at most one
+// level deep: a tag whose parent is in tags must not have a parent with a
+// parent of its own. Field paths are prefixed with the index of the tag, such
+// as "[2].parent".
+func ValidateTags(tags []Tag) error {
+	parents := make(map[string]*string, len(tags))
+	for _, tag := range tags {
+		parents[tag.ID] = tag.Parent
+	}
+
+	v := validator{}
+	for i, tag := range tags {
+		prefix := fmt.Sprintf("[%d].", i)
+		v.merge(prefix, tag.Validate())
+		if tag.Parent == nil {
+			continue
+		}
+		if grandparent := parents[*tag.Parent]; grandparent != nil {
+			v.add(prefix+"parent", "must be a top-level tag; tags nest at most one level deep")
+		}
+	}
+
+	return v.err("tags")
+}
+
+// Validate checks the month, tag, and amounts of a budget. Budgets are set
+// for whole months, so Date must be the first day of a month.
+func (b Budget) Validate() error {
+	v := validator{}
+	v.positive("user", b.User)
+	if v.date("date", b.Date) {
+		if date, _ := time.Parse(time.DateOnly, b.Date); date.Day() != 1 {
+			v.add("date", "must be the first day of a month")
+		}
+	}
+	v.optionalUUID("tag", b.Tag)
+	v.nonNegative("income", b.Income)
+	v.nonNegative("outcome", b.Outcome)
+
+	return v.err("budget")
+}
+
+// Validate checks the identifiers and title of a merchant.
+func (m Merchant) Validate() error {
+	v := validator{}
+	v.uuid("id", m.ID)
+	v.positive("user", m.User)
+	v.required("title", m.Title)
+	if m.MCC != nil && (*m.MCC < 0 || *m.MCC > maxMerchantCode) {
+		v.add("mcc", "must be between 0 and 9999")
+	}
+
+	return v.err("merchant")
+}
+
+// Validate checks the accounts, instruments, amounts, dates, and schedule of
+// a reminder.
+func (r Reminder) Validate() error {
+	v := validator{}
+	v.uuid("id", r.ID)
+	v.positive("user", r.User)
+	v.operation(r.IncomeAccount, r.OutcomeAccount, r.IncomeInstrument, r.OutcomeInstrument, r.Income, r.Outcome)
+	v.tags(r.Tag)
+	start := v.date("startDate", r.StartDate)
+	if v.optionalDate("endDate", r.EndDate) && start && *r.EndDate < r.StartDate {
+		v.add("endDate", "must not be before startDate")
+	}
+	if r.Interval != nil {
+		v.interval("interval", r.RepeatInterval())
+		if r.Step <= 0 {
+			v.add("step", "must be positive when interval is set")
+		}
+		for i, point := range r.Points {
+			if point < 0 || (r.Step > 0 && point >= r.Step) {
+				v.add(fmt.Sprintf("points[%d]", i), "must be between 0 and step - 1")
+			}
+		}
+	}
+	v.optionalUUID("merchant", r.Merchant)
+
+	return v.err("reminder")
+}
+
+// Validate checks the reminder, accounts, instruments, amounts, date, and
+// state of a reminder marker.
+func (m ReminderMarker) Validate() error {
+	v := validator{}
+	v.uuid("id", m.ID)
+	v.positive("user", m.User)
+	v.date("date", m.Date)
+	v.operation(m.IncomeAccount, m.OutcomeAccount, m.IncomeInstrument, m.OutcomeInstrument, m.Income, m.Outcome)
+	v.tags(m.Tag)
+	v.enum("state", m.State, m.MarkerState().IsValid())
+	v.uuid("reminder", m.Reminder)
+	v.optionalUUID("merchant", m.Merchant)
+
+	return v.err("reminderMarker")
+}
+
+// Validate checks the accounts, instruments, amounts, date, and references of
+// a transaction. Deleted transactions only need their identifiers and date.
+func (t Transaction) Validate() error {
+	v := validator{}
+	v.uuid("id", t.ID)
+	v.positive("user", t.User)
+	v.date("date", t.Date)
+	if t.Deleted {
+		return v.err("transaction")
+	}
+
+	outcomeAccount := ""
+	if t.OutcomeAccount != nil {
+		outcomeAccount = *t.OutcomeAccount
+	}
+	v.operation(t.IncomeAccount, outcomeAccount, t.IncomeInstrument, t.OutcomeInstrument, t.Income, t.Outcome)
+	v.tags(t.Tag)
+	v.nonNegative("opIncome", t.OpIncome)
+	v.nonNegative("opOutcome", t.OpOutcome)
+	if t.OpIncome > 0 && (t.OpIncomeInstrument == nil || *t.OpIncomeInstrument <= 0) {
+		v.add("opIncomeInstrument", "is required when opIncome is set")
+	}
+	if t.OpOutcome > 0 && (t.OpOutcomeInstrument == nil || *t.OpOutcomeInstrument <= 0) {
+		v.add("opOutcomeInstrument", "is required when opOutcome is set")
+	}
+	if t.Latitude != nil && (*t.Latitude < -90 || *t.Latitude > 90) {
+		v.add("latitude", "must be between -90 and 90")
+	}
+	if t.Longitude != nil && (*t.Longitude < -180 || *t.Longitude > 180) {
+		v.add("longitude", "must be between -180 and 180")
+	}
+	v.optionalUUID("merchant", t.Merchant)
+	v.optionalUUID("reminderMarker", t.ReminderMarker)
+
+	return v.err("transaction")
+}
+
+// validator collects field errors in the order they are found.
+type validator struct {
+	fields []*errors.FieldError
+}
+
+func (v *validator) add(field string, message string) {
+	v.fields = append(v.fields, &errors.FieldError{Field: field, Message: message})
+}
+
+func (v *validator) err(entity string) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+
+	return errors.New(
+		errors.ErrInvalidRequest,
+		"validation failed",
+		&errors.ValidationError{Entity: entity, Fields: v.fields},
+	)
+}
+
+// merge adds the field errors of err with prefix prepended to their paths.
+func (v *validator) merge(prefix string, err error) {
+	var validationErr *errors.ValidationError
+	if stdErrors.As(err, &validationErr) {
+		for _, field := range validationErr.Fields {
+			v.add(prefix+field.Field, field.Message)
+		}
+	}
+}
+
+func (v *validator) required(field string, value string) bool {
+	if value == "" {
+		v.add(field, "is required")
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) uuid(field string, value string) {
+	if v.required(field, value) && !uuidPattern.MatchString(value) {
+		v.add(field, "must be a UUID")
+	}
+}
+
+func (v *validator) optionalUUID(field string, value *string) {
+	if value != nil {
+		v.uuid(field, *value)
+	}
+}
+
+// date reports whether value is a valid date.
+func (v *validator) date(field string, value string) bool {
+	if !v.required(field, value) {
+		return false
+	}
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
+		v.add(field, "must be a date in yyyy-MM-dd format")
+		return false
+	}
+
+	return true
+}
+
+// optionalDate reports whether value is set and valid.
+func (v *validator) optionalDate(field string, value *string) bool {
+	return value != nil && v.date(field, *value)
+}
+
+func (v *validator) positive(field string, value int) {
+	if value <= 0 {
+		v.add(field, "must be positive")
+	}
+}
+
+func (v *validator) nonNegative(field string, value float64) {
+	if value < 0 {
+		v.add(field, "must not be negative")
+	}
+}
+
+func (v *validator) optionalNonNegative(field string, value *float64) {
+	if value != nil {
+		v.nonNegative(field, *value)
+	}
+}
+
+// enum checks a value of an enumerated type; valid is the result of its
+// IsValid method.
+func (v *validator) enum(field string, value string, valid bool) {
+	if v.required(field, value) && !valid {
+		v.add(field, fmt.Sprintf("must be a known value, got %q", value))
+	}
+}
+
+func (v *validator) interval(field string, value Interval) {
+	v.enum(field, string(value), value.IsValid())
+}
+
+func (v *validator) tags(tags []string) {
+	for i, tag := range tags {
+		v.uuid(fmt.Sprintf("tag[%d]", i), tag)
+	}
+}
+
+// operation checks the account, instrument, and amount fields shared by
+// transactions, reminders, and reminder markers.
+func (v *validator) operation(incomeAccount, outcomeAccount string, incomeInstrument, outcomeInstrument int, income, outcome float64) {
+	v.uuid("incomeAccount", incomeAccount)
+	v.uuid("outcomeAccount", outcomeAccount)
+	v.positive("incomeInstrument", incomeInstrument)
+	v.positive("outcomeInstrument", outcomeInstrument)
+	v.nonNegative("income", income)
+	v.nonNegative("outcome", outcome)
+}
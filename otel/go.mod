@@ -0,0 +1,31 @@
+module github.com/nemirlev/zenmoney-go-sdk/otel
+
+go 1.26.0
+
+toolchain go1.27.0
+
+require (
+	github.com/nemirlev/zenmoney-go-sdk/v3 v3.1.0
+	github.com/stretchr/testify v1.12.1
+	go.opentelemetry.io/otel v1.47.0
+	go.opentelemetry.io/otel/metric v1.47.0
+	go.opentelemetry.io/otel/sdk v1.47.0
+	go.opentelemetry.io/otel/sdk/metric v1.47.0
+	go.opentelemetry.io/otel/trace v1.47.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/go-logr/logr v1.4.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	go.opentelemetry.io/otel/log v1.47.0 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/sys v0.48.0 // indirect
+)
+
+// The replace directive builds against the SDK in this repository during
+// development. Modules that depend on otel ignore it and use the SDK version
+// required above.
+replace github.com/nemirlev/zenmoney-go-sdk/v3 => ..
@@ -0,0 +1,222 @@
+// Package otel instruments the ZenMoney client with OpenTelemetry.
+//
+// WithTelemetry returns a single client option that records a span for every
+// SDK operation, a child span for every HTTP attempt, and metrics for
+// operation latency, attempt latency, retries, response sizes, and error
+// codes. Spans and metrics carry the endpoint, HTTP status, request ID, and
+// attempt number; request and response bodies are never recorded.
+//
+//	client, err := api.NewClient(token, otel.WithTelemetry())
+//
+// Without options the global tracer and meter providers are used.
+package otel
+
+import (
+	"context"
+	stdErrors "errors"
+	"strings"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/api"
+	otelglobal "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/nemirlev/zenmoney-go-sdk/otel"
+
+// Attribute keys recorded on spans and metrics.
+const (
+	AttributeEndpoint   = attribute.Key("zenmoney.endpoint")
+	AttributeRequestID  = attribute.Key("zenmoney.request_id")
+	AttributeAttempt    = attribute.Key("zenmoney.attempt")
+	AttributeRetryCount = attribute.Key("zenmoney.retry_count")
+	AttributeMethod     = attribute.Key("http.request.method")
+	AttributeStatusCode = attribute.Key("http.response.status_code")
+	AttributeBodySize   = attribute.Key("http.response.body.size")
+	AttributeErrorType  = attribute.Key("error.type")
+)
+
+// Option configures WithTelemetry.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the tracer provider. The global provider is used by
+// default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. The global provider is used by
+// default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithTelemetry returns a client option that installs tracing and metrics.
+// Errors creating instruments are reported to the global OpenTelemetry error
+// handler.
+func WithTelemetry(opts ...Option) api.Option {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.tracerProvider == nil {
+		cfg.tracerProvider = otelglobal.GetTracerProvider()
+	}
+	if cfg.meterProvider == nil {
+		cfg.meterProvider = otelglobal.GetMeterProvider()
+	}
+
+	t := newTelemetry(cfg)
+	operation := api.WithOperationInterceptors(t.operation)
+	attempt := api.WithInterceptors(t.attempt)
+
+	return func(c *api.Config) {
+		operation(c)
+		attempt(c)
+	}
+}
+
+type telemetry struct {
+	tracer trace.Tracer
+
+	operationDuration metric.Float64Histogram
+	attemptDuration   metric.Float64Histogram
+	responseSize      metric.Int64Histogram
+	retries           metric.Int64Counter
+	errors            metric.Int64Counter
+}
+
+func newTelemetry(cfg config) *telemetry {
+	meter := cfg.meterProvider.Meter(ScopeName)
+	t := &telemetry{tracer: cfg.tracerProvider.Tracer(ScopeName)}
+
+	var err error
+	t.operationDuration, err = meter.Float64Histogram("zenmoney.client.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of SDK operations including retries."))
+	handle(err)
+	t.attemptDuration, err = meter.Float64Histogram("zenmoney.client.attempt.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of individual HTTP attempts."))
+	handle(err)
+	t.responseSize, err = meter.Int64Histogram("zenmoney.client.response.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of successful response bodies as received, before decompression."))
+	handle(err)
+	t.retries, err = meter.Int64Counter("zenmoney.client.retries",
+		metric.WithUnit("{retry}"),
+		metric.WithDescription("Number of retried HTTP attempts."))
+	handle(err)
+	t.errors, err = meter.Int64Counter("zenmoney.client.errors",
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Number of failed SDK operations by error code."))
+	handle(err)
+
+	return t
+}
+
+func (t *telemetry) operation(ctx context.Context, call *api.Call, next api.Handler) error {
+	startedAt := time.Now()
+	ctx, span := t.tracer.Start(ctx, "zenmoney "+operationName(call.Endpoint),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(AttributeEndpoint.String(call.Endpoint)),
+	)
+	defer span.End()
+
+	err := next(ctx, call)
+
+	retries := max(call.Attempt-1, 0)
+	span.SetAttributes(AttributeRetryCount.Int(retries))
+	attrs := []attribute.KeyValue{AttributeEndpoint.String(call.Endpoint)}
+	if retries > 0 {
+		t.retries.Add(ctx, int64(retries), metric.WithAttributes(attrs...))
+	}
+	if err != nil {
+		code := errorType(err)
+		recordError(span, err, code)
+		attrs = append(attrs, AttributeErrorType.String(code))
+		t.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+	t.operationDuration.Record(ctx, time.Since(startedAt).Seconds(), metric.WithAttributes(attrs...))
+
+	return err
+}
+
+func (t *telemetry) attempt(ctx context.Context, call *api.Call, next api.Handler) error {
+	startedAt := time.Now()
+	ctx, span := t.tracer.Start(ctx, call.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttributeMethod.String(call.Method),
+			AttributeEndpoint.String(call.Endpoint),
+			AttributeAttempt.Int(call.Attempt),
+		),
+	)
+	defer span.End()
+
+	err := next(ctx, call)
+
+	attrs := []attribute.KeyValue{AttributeEndpoint.String(call.Endpoint)}
+	if call.StatusCode != 0 {
+		span.SetAttributes(AttributeStatusCode.Int(call.StatusCode))
+		attrs = append(attrs, AttributeStatusCode.Int(call.StatusCode))
+	}
+	if call.RequestID != "" {
+		span.SetAttributes(AttributeRequestID.String(call.RequestID))
+	}
+	if err != nil {
+		code := errorType(err)
+		recordError(span, err, code)
+		attrs = append(attrs, AttributeErrorType.String(code))
+	} else {
+		span.SetAttributes(AttributeBodySize.Int64(call.ResponseSize))
+		t.responseSize.Record(ctx, call.ResponseSize, metric.WithAttributes(attrs...))
+	}
+	t.attemptDuration.Record(ctx, time.Since(startedAt).Seconds(), metric.WithAttributes(attrs...))
+
+	return err
+}
+
+// operationName maps an endpoint such as "diff/" to a span name component.
+func operationName(endpoint string) string {
+	switch name := strings.Trim(endpoint, "/"); name {
+	case "diff":
+		return "sync"
+	default:
+		return name
+	}
+}
+
+func errorType(err error) string {
+	var apiErr *api.Error
+	if stdErrors.As(err, &apiErr) {
+		return string(apiErr.Code)
+	}
+
+	return "_OTHER"
+}
+
+func recordError(span trace.Span, err error, errorType string) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.SetAttributes(AttributeErrorType.String(errorType))
+}
+
+func handle(err error) {
+	if err != nil {
+		otelglobal.Handle(err)
+	}
+}
@@ -0,0 +1,131 @@
+package otel_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	zenotel "github.com/nemirlev/zenmoney-go-sdk/otel"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/api"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newClient(t *testing.T, transport roundTripFunc) (*api.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	client, err := api.NewClient(
+		"test-token",
+		api.WithHTTPClient(&http.Client{Transport: transport}),
+		api.WithRetryPolicy(2, 0),
+		zenotel.WithTelemetry(
+			zenotel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+			zenotel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		),
+	)
+	require.NoError(t, err)
+
+	return client, spans, reader
+}
+
+func TestWithTelemetryRecordsSpans(t *testing.T) {
+	attempts := 0
+	client, spans, _ := newClient(t, func(*http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, stdErrors.New("temporary failure")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-Request-Id": []string{"request-123"}},
+			Body:       io.NopCloser(strings.NewReader(`{"serverTimestamp":1}`)),
+		}, nil
+	})
+
+	_, err := client.FullSync(context.Background())
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+	first, second, operation := ended[0], ended[1], ended[2]
+
+	require.Equal(t, "zenmoney sync", operation.Name())
+	require.Contains(t, operation.Attributes(), zenotel.AttributeRetryCount.Int(1))
+	require.Equal(t, codes.Unset, operation.Status().Code)
+
+	for _, attempt := range []sdktrace.ReadOnlySpan{first, second} {
+		require.Equal(t, "POST", attempt.Name())
+		require.Equal(t, trace.SpanKindClient, attempt.SpanKind())
+		require.Equal(t, operation.SpanContext().SpanID(), attempt.Parent().SpanID())
+		require.Contains(t, attempt.Attributes(), zenotel.AttributeEndpoint.String("diff/"))
+	}
+	require.Contains(t, first.Attributes(), zenotel.AttributeAttempt.Int(1))
+	require.Contains(t, first.Attributes(), zenotel.AttributeErrorType.String(string(api.ErrNetworkError)))
+	require.Equal(t, codes.Error, first.Status().Code)
+	require.Contains(t, second.Attributes(), zenotel.AttributeAttempt.Int(2))
+	require.Contains(t, second.Attributes(), zenotel.AttributeStatusCode.Int(http.StatusOK))
+	require.Contains(t, second.Attributes(), zenotel.AttributeRequestID.String("request-123"))
+	require.Contains(t, second.Attributes(), zenotel.AttributeBodySize.Int64(int64(len(`{"serverTimestamp":1}`))))
+}
+
+func TestWithTelemetryRecordsMetrics(t *testing.T) {
+	attempts := 0
+	client, _, reader := newClient(t, func(*http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, stdErrors.New("temporary failure")
+		}
+		return &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`slow down`)),
+		}, nil
+	})
+
+	_, err := client.FullSync(context.Background())
+	require.Error(t, err)
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	require.Equal(t, zenotel.ScopeName, data.ScopeMetrics[0].Scope.Name)
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	retries := metrics["zenmoney.client.retries"].(metricdata.Sum[int64])
+	require.Equal(t, int64(1), retries.DataPoints[0].Value)
+
+	errorCounts := metrics["zenmoney.client.errors"].(metricdata.Sum[int64])
+	require.Len(t, errorCounts.DataPoints, 1)
+	require.Equal(t, int64(1), errorCounts.DataPoints[0].Value)
+	errorType, ok := errorCounts.DataPoints[0].Attributes.Value(attribute.Key(zenotel.AttributeErrorType))
+	require.True(t, ok)
+	require.Equal(t, string(api.ErrRateLimit), errorType.AsString())
+
+	operations := metrics["zenmoney.client.operation.duration"].(metricdata.Histogram[float64])
+	require.Equal(t, uint64(1), operations.DataPoints[0].Count)
+	attemptDurations := metrics["zenmoney.client.attempt.duration"].(metricdata.Histogram[float64])
+	var attemptCount uint64
+	for _, point := range attemptDurations.DataPoints {
+		attemptCount += point.Count
+	}
+	require.Equal(t, uint64(2), attemptCount)
+}
@@ -0,0 +1,279 @@
+// Package recurring detects recurring payments such as subscriptions in the
+// transaction history.
+//
+// Detect groups expenses by merchant or payee, account currency, and similar
+// amount, and reports the groups whose dates repeat at a regular weekly,
+// monthly, or yearly interval:
+//
+//	for _, series := range recurring.Detect(snapshot, recurring.Options{}) {
+//		fmt.Printf("%s: %.2f per month, next on %s\n",
+//			series.Name, series.MonthlyCost, series.NextDate.Format(time.DateOnly))
+//	}
+//
+// Every series can be turned into a models.Reminder with Series.Reminder so
+// that ZenMoney plans the next payments.
+package recurring
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// monthDays is the length of an average month.
+const monthDays = 365.25 / 12
+
+// Options controls detection. Zero values select the defaults.
+type Options struct {
+	// MinOccurrences is the number of payments a series needs. It defaults
+	// to 3.
+	MinOccurrences int
+
+	// AmountTolerance is the relative difference allowed between the amounts
+	// of a series. It defaults to 0.1, so 9.50 and 10.40 match 10.
+	AmountTolerance float64
+
+	// IntervalTolerance is the relative difference allowed between an
+	// interval and its nominal length, with a minimum of two days. It
+	// defaults to 0.15, so monthly payments may drift by four days.
+	IntervalTolerance float64
+}
+
+// Series is a detected recurring payment.
+type Series struct {
+	// Key is the merchant ID or the normalized payee of the payments.
+	Key string
+
+	// Name is the merchant title or the payee of the latest payment.
+	Name string
+
+	// Merchant is the merchant ID, or empty when payments only have a payee.
+	Merchant string
+
+	// Account and Instrument are taken from the latest payment.
+	Account    string
+	Instrument int
+
+	// Interval and Step describe the period, such as every 1 IntervalMonth or
+	// every 2 IntervalWeek.
+	Interval models.Interval
+	Step     int
+
+	// Amount is the median payment.
+	Amount float64
+
+	// MonthlyCost is Amount spread over an average month.
+	MonthlyCost float64
+
+	// LastDate is the date of the latest payment and NextDate the expected
+	// date of the next one.
+	LastDate time.Time
+	NextDate time.Time
+
+	// Transactions lists the payments by date.
+	Transactions []models.Transaction
+}
+
+// period is a recognized recurrence.
+type period struct {
+	interval models.Interval
+	step     int
+	days     float64
+}
+
+var periods = []period{
+	{models.IntervalWeek, 1, 7},
+	{models.IntervalWeek, 2, 14},
+	{models.IntervalMonth, 1, monthDays},
+	{models.IntervalMonth, 2, 2 * monthDays},
+	{models.IntervalMonth, 3, 3 * monthDays},
+	{models.IntervalMonth, 6, 6 * monthDays},
+	{models.IntervalYear, 1, 365.25},
+}
+
+// Detect returns the recurring payments among the expenses of snapshot,
+// sorted by descending monthly cost. Deleted transactions, transfers, and
+// income are ignored.
+func Detect(snapshot models.Response, opts Options) []Series {
+	opts = withDefaults(opts)
+	merchants := make(map[string]string, len(snapshot.Merchant))
+	for _, merchant := range snapshot.Merchant {
+		merchants[merchant.ID] = merchant.Title
+	}
+
+	type groupKey struct {
+		key        string
+		instrument int
+	}
+	groups := make(map[groupKey][]payment)
+	for _, transaction := range snapshot.Transaction {
+		if !finance.IsExpense(transaction) {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, transaction.Date)
+		if err != nil {
+			continue
+		}
+		key := counterpartyOf(transaction)
+		if key == "" {
+			continue
+		}
+		id := groupKey{key: key, instrument: transaction.OutcomeInstrument}
+		groups[id] = append(groups[id], payment{transaction: transaction, date: date})
+	}
+
+	var result []Series
+	for id, payments := range groups {
+		for _, cluster := range clusterByAmount(payments, opts.AmountTolerance) {
+			if len(cluster) < opts.MinOccurrences {
+				continue
+			}
+			if series, ok := detect(id.key, cluster, merchants, opts); ok {
+				result = append(result, series)
+			}
+		}
+	}
+	slices.SortFunc(result, func(a, b Series) int {
+		return cmp.Or(cmp.Compare(b.MonthlyCost, a.MonthlyCost), cmp.Compare(a.Key, b.Key), cmp.Compare(a.Amount, b.Amount))
+	})
+
+	return result
+}
+
+// Reminder returns a new reminder that repeats the series from NextDate on.
+// The reminder carries the merchant of the series and the payee and tags of
+// the latest payment, if the series has payments.
+func (s Series) Reminder(user int, opts ...models.NewOption) models.Reminder {
+	reminder := models.NewReminder(user, s.Account, s.Amount, s.Instrument, s.NextDate, string(s.Interval), s.Step, opts...)
+	if s.Merchant != "" {
+		merchant := s.Merchant
+		reminder.Merchant = &merchant
+	}
+	if len(s.Transactions) == 0 {
+		return reminder
+	}
+	latest := s.Transactions[len(s.Transactions)-1]
+	if latest.Payee != "" {
+		payee := latest.Payee
+		reminder.Payee = &payee
+	}
+	reminder.Tag = slices.Clone(latest.Tag)
+
+	return reminder
+}
+
+type payment struct {
+	transaction models.Transaction
+	date        time.Time
+}
+
+func withDefaults(opts Options) Options {
+	if opts.MinOccurrences <= 0 {
+		opts.MinOccurrences = 3
+	}
+	if opts.AmountTolerance <= 0 {
+		opts.AmountTolerance = 0.1
+	}
+	if opts.IntervalTolerance <= 0 {
+		opts.IntervalTolerance = 0.15
+	}
+
+	return opts
+}
+
+func counterpartyOf(transaction models.Transaction) string {
+	if transaction.Merchant != nil && *transaction.Merchant != "" {
+		return *transaction.Merchant
+	}
+
+	return finance.NormalizePayee(transaction.Payee)
+}
+
+// clusterByAmount splits payments into groups whose amounts lie within
+// tolerance of the smallest amount of the group.
+func clusterByAmount(payments []payment, tolerance float64) [][]payment {
+	slices.SortFunc(payments, func(a, b payment) int {
+		return cmp.Compare(a.transaction.Outcome, b.transaction.Outcome)
+	})
+
+	var clusters [][]payment
+	start := 0
+	for i := 1; i <= len(payments); i++ {
+		if i == len(payments) || payments[i].transaction.Outcome > payments[start].transaction.Outcome*(1+tolerance) {
+			clusters = append(clusters, payments[start:i])
+			start = i
+		}
+	}
+
+	return clusters
+}
+
+// detect reports whether the payments of a cluster repeat at a recognized
+// period and describes the series.
+func detect(key string, payments []payment, merchants map[string]string, opts Options) (Series, bool) {
+	payments = slices.Clone(payments)
+	slices.SortFunc(payments, func(a, b payment) int {
+		return cmp.Or(a.date.Compare(b.date), cmp.Compare(a.transaction.Created, b.transaction.Created))
+	})
+
+	gaps := make([]float64, len(payments)-1)
+	for i := 1; i < len(payments); i++ {
+		gaps[i-1] = payments[i].date.Sub(payments[i-1].date).Hours() / 24
+	}
+	p, ok := match(finance.Median(gaps), opts.IntervalTolerance)
+	if !ok {
+		return Series{}, false
+	}
+	for _, gap := range gaps {
+		if math.Abs(gap-p.days) > tolerance(p, opts.IntervalTolerance) {
+			return Series{}, false
+		}
+	}
+
+	amounts := make([]float64, len(payments))
+	transactions := make([]models.Transaction, len(payments))
+	for i, payment := range payments {
+		amounts[i] = payment.transaction.Outcome
+		transactions[i] = payment.transaction
+	}
+	latest := payments[len(payments)-1]
+	series := Series{
+		Key:          key,
+		Name:         latest.transaction.Payee,
+		Account:      *latest.transaction.OutcomeAccount,
+		Instrument:   latest.transaction.OutcomeInstrument,
+		Interval:     p.interval,
+		Step:         p.step,
+		Amount:       finance.Round(finance.Median(amounts)),
+		LastDate:     latest.date,
+		NextDate:     finance.AddInterval(latest.date, p.interval, p.step),
+		Transactions: transactions,
+	}
+	if latest.transaction.Merchant != nil && *latest.transaction.Merchant != "" {
+		series.Merchant = *latest.transaction.Merchant
+		if title := merchants[series.Merchant]; title != "" {
+			series.Name = title
+		}
+	}
+	series.MonthlyCost = finance.Round(series.Amount * monthDays / p.days)
+
+	return series, true
+}
+
+func match(gap float64, relative float64) (period, bool) {
+	for _, p := range periods {
+		if math.Abs(gap-p.days) <= tolerance(p, relative) {
+			return p, true
+		}
+	}
+
+	return period{}, false
+}
+
+func tolerance(p period, relative float64) float64 {
+	return max(p.days*relative, 2)
+}
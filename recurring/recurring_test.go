@@ -0,0 +1,113 @@
+package recurring_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/txtest"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/recurring"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	card          = "0f9b7c2e-5d4a-4e1b-9c3d-2a6f8e1b7c01"
+	subscriptions = "0f9b7c2e-5d4a-4e1b-9c3d-2a6f8e1b7c02"
+)
+
+func date(value string) time.Time {
+	result, _ := time.Parse(time.DateOnly, value)
+	return result
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestDetect(t *testing.T) {
+	music := "music"
+	withMerchant := func(tr models.Transaction) models.Transaction {
+		tr.Merchant = &music
+		return tr
+	}
+	snapshot := models.Response{
+		Merchant: []models.Merchant{{ID: music, Title: "Music Service"}},
+		Transaction: []models.Transaction{
+			withMerchant(txtest.Expense("m1", "2024-01-31", 9.99, txtest.Payee("MUSIC*1"))),
+			withMerchant(txtest.Expense("m2", "2024-02-29", 9.99, txtest.Payee("MUSIC*2"))),
+			withMerchant(txtest.Expense("m3", "2024-03-31", 10.49, txtest.Payee("MUSIC*3"))),
+			txtest.Expense("g1", "2024-01-01", 20, txtest.Payee("Gym")),
+			txtest.Expense("g2", "2024-01-08", 20, txtest.Payee("GYM")),
+			txtest.Expense("g3", "2024-01-15", 21, txtest.Payee("gym")),
+			txtest.Expense("g4", "2024-01-23", 20, txtest.Payee("Gym")),
+			txtest.Expense("c1", "2024-01-03", 5, txtest.Payee("Cafe")),
+			txtest.Expense("c2", "2024-01-04", 50, txtest.Payee("Cafe")),
+			txtest.Expense("c3", "2024-02-20", 5, txtest.Payee("Cafe")),
+			txtest.Expense("c4", "2024-03-01", 5, txtest.Payee("Cafe")),
+			txtest.Expense("s1", "2024-01-05", 100, txtest.Payee("Store")),
+			txtest.Expense("s2", "2024-02-05", 100, txtest.Payee("Store")),
+		},
+	}
+
+	series := recurring.Detect(snapshot, recurring.Options{})
+
+	require.Len(t, series, 2)
+	gym := series[0]
+	require.Equal(t, "gym", gym.Key)
+	require.Equal(t, "Gym", gym.Name)
+	require.Equal(t, models.IntervalWeek, gym.Interval)
+	require.Equal(t, 1, gym.Step)
+	require.Equal(t, 20.0, gym.Amount)
+	require.Equal(t, 86.96, gym.MonthlyCost)
+	require.Equal(t, date("2024-01-30"), gym.NextDate)
+	require.Len(t, gym.Transactions, 4)
+
+	subscription := series[1]
+	require.Equal(t, "Music Service", subscription.Name)
+	require.Equal(t, music, subscription.Merchant)
+	require.Equal(t, models.IntervalMonth, subscription.Interval)
+	require.Equal(t, 9.99, subscription.Amount)
+	require.Equal(t, 9.99, subscription.MonthlyCost)
+	require.Equal(t, date("2024-03-31"), subscription.LastDate)
+	require.Equal(t, date("2024-04-30"), subscription.NextDate)
+	require.Equal(t, []string{"m1", "m2", "m3"}, []string{
+		subscription.Transactions[0].ID, subscription.Transactions[1].ID, subscription.Transactions[2].ID,
+	})
+}
+
+func TestSeriesReminder(t *testing.T) {
+	series := recurring.Detect(models.Response{Transaction: []models.Transaction{
+		txtest.Expense("1", "2024-01-10", 15, txtest.FromAccount(card), txtest.Tags(subscriptions), txtest.Payee("Video")),
+		txtest.Expense("2", "2024-02-10", 15, txtest.FromAccount(card), txtest.Tags(subscriptions), txtest.Payee("Video")),
+		txtest.Expense("3", "2024-03-11", 15, txtest.FromAccount(card), txtest.Tags(subscriptions), txtest.Payee("Video")),
+	}}, recurring.Options{})
+	require.Len(t, series, 1)
+
+	reminder := series[0].Reminder(7,
+		models.WithIDGenerator(func() string { return "00000000-0000-4000-8000-000000000001" }),
+		models.WithClock(fixedClock(date("2024-03-12"))),
+	)
+
+	require.NoError(t, reminder.Validate())
+	require.Equal(t, "2024-04-11", reminder.StartDate)
+	require.Equal(t, models.IntervalMonth, reminder.RepeatInterval())
+	require.Equal(t, 1, reminder.Step)
+	require.Equal(t, 15.0, reminder.Outcome)
+	require.Equal(t, "Video", *reminder.Payee)
+	require.Equal(t, []string{subscriptions}, reminder.Tag)
+	require.Equal(t, card, reminder.OutcomeAccount)
+}
+
+func TestSeriesReminderWithoutPayments(t *testing.T) {
+	series := recurring.Series{Account: card, Instrument: 1, Amount: 10, Interval: models.IntervalMonth, Step: 1, NextDate: date("2024-04-01")}
+
+	reminder := series.Reminder(7)
+
+	require.Equal(t, card, reminder.OutcomeAccount)
+	require.Equal(t, "2024-04-01", reminder.StartDate)
+	require.Nil(t, reminder.Payee)
+	require.Empty(t, reminder.Tag)
+	require.NotPanics(t, func() { recurring.Series{}.Reminder(7) })
+}
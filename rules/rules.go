@@ -0,0 +1,325 @@
+// Package rules provides deterministic, rule-based transaction categorization.
+//
+// An Engine evaluates an ordered list of rules against transactions and
+// assigns tags, merchants, and payees from the first rule that matches. Rules
+// can be loaded from JSON or YAML, every assignment is explained, and
+// ApplyWithFallback asks ZenMoney for suggestions only for transactions that
+// no rule matched.
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"go.yaml.in/yaml/v3"
+)
+
+// Rule assigns categorization values to transactions that satisfy all of its
+// conditions.
+type Rule struct {
+	// Name identifies the rule in explanations.
+	Name string    `json:"name" yaml:"name"`
+	When Condition `json:"when" yaml:"when"`
+	Then Action    `json:"then" yaml:"then"`
+}
+
+// Condition describes the transactions a rule applies to. Empty fields are
+// not checked, and every non-empty field must match.
+type Condition struct {
+	// Payee is a regular expression matched case-insensitively against
+	// Transaction.OriginalPayee and Transaction.Payee.
+	Payee string `json:"payee,omitempty" yaml:"payee,omitempty"`
+
+	// MCC lists merchant category codes. The code is taken from the Merchant
+	// referenced by Transaction.Merchant.
+	MCC []int `json:"mcc,omitempty" yaml:"mcc,omitempty"`
+
+	// MinAmount and MaxAmount bound the transaction amount, inclusive. The
+	// amount is Transaction.Outcome for expenses and Transaction.Income
+	// otherwise.
+	MinAmount *float64 `json:"minAmount,omitempty" yaml:"minAmount,omitempty"`
+	MaxAmount *float64 `json:"maxAmount,omitempty" yaml:"maxAmount,omitempty"`
+
+	// Account is an Account.ID used as either side of the transaction.
+	Account string `json:"account,omitempty" yaml:"account,omitempty"`
+}
+
+// Action lists the values assigned by a matching rule. Empty fields leave the
+// transaction unchanged.
+type Action struct {
+	// Tag replaces Transaction.Tag. Values are Tag.ID.
+	Tag []string `json:"tag,omitempty" yaml:"tag,omitempty"`
+
+	// Merchant sets Transaction.Merchant to a Merchant.ID.
+	Merchant string `json:"merchant,omitempty" yaml:"merchant,omitempty"`
+
+	// Payee sets Transaction.Payee.
+	Payee string `json:"payee,omitempty" yaml:"payee,omitempty"`
+}
+
+// Source identifies where the categorization of a transaction came from.
+type Source string
+
+const (
+	SourceNone    Source = "none"
+	SourceRule    Source = "rule"
+	SourceSuggest Source = "suggest"
+)
+
+// Result is the outcome of categorizing one transaction.
+type Result struct {
+	Transaction models.Transaction
+	Source      Source
+
+	// Rule is the name of the matching rule when Source is SourceRule.
+	Rule string
+	// RuleIndex is the position of the matching rule, or -1.
+	RuleIndex int
+	// Reasons describes each condition that matched, for example
+	// `payee "Starbucks" matches "starbucks|costa"`.
+	Reasons []string
+}
+
+// Suggester requests remote suggestions. *api.Client implements Suggester.
+type Suggester interface {
+	SuggestBatch(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error)
+}
+
+// Engine evaluates rules in order. An Engine is safe for concurrent use.
+type Engine struct {
+	rules     []compiledRule
+	merchants map[string]models.Merchant
+}
+
+type compiledRule struct {
+	Rule
+	payee *regexp.Regexp
+}
+
+// NewEngine compiles rules for evaluation. merchants supplies the merchant
+// category codes used by MCC conditions and is usually Response.Merchant.
+// NewEngine returns an *errors.Error when a rule has no conditions, no
+// actions, an invalid payee expression, or an inverted amount range.
+func NewEngine(rules []Rule, merchants []models.Merchant) (*Engine, error) {
+	engine := &Engine{
+		rules:     make([]compiledRule, 0, len(rules)),
+		merchants: make(map[string]models.Merchant, len(merchants)),
+	}
+	for _, merchant := range merchants {
+		engine.merchants[merchant.ID] = merchant
+	}
+
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = "#" + strconv.Itoa(i)
+		}
+		when := rule.When
+		if when.Payee == "" && len(when.MCC) == 0 && when.MinAmount == nil && when.MaxAmount == nil && when.Account == "" {
+			return nil, errors.New(errors.ErrInvalidRequest, fmt.Sprintf("rule %s has no conditions", name), nil)
+		}
+		if len(rule.Then.Tag) == 0 && rule.Then.Merchant == "" && rule.Then.Payee == "" {
+			return nil, errors.New(errors.ErrInvalidRequest, fmt.Sprintf("rule %s has no actions", name), nil)
+		}
+		if when.MinAmount != nil && when.MaxAmount != nil && *when.MinAmount > *when.MaxAmount {
+			return nil, errors.New(errors.ErrInvalidRequest, fmt.Sprintf("rule %s has minAmount greater than maxAmount", name), nil)
+		}
+
+		compiled := compiledRule{Rule: rule}
+		compiled.Name = name
+		if when.Payee != "" {
+			payee, err := regexp.Compile("(?i)" + when.Payee)
+			if err != nil {
+				return nil, errors.New(errors.ErrInvalidRequest, fmt.Sprintf("rule %s has invalid payee expression", name), err)
+			}
+			compiled.payee = payee
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+
+	return engine, nil
+}
+
+// LoadJSON decodes a JSON array of rules from r.
+func LoadJSON(r io.Reader) ([]Rule, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var rules []Rule
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, errors.New(errors.ErrInvalidRequest, "failed to decode JSON rules", err)
+	}
+
+	return rules, nil
+}
+
+// LoadYAML decodes a YAML sequence of rules from r.
+func LoadYAML(r io.Reader) ([]Rule, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var rules []Rule
+	if err := decoder.Decode(&rules); err != nil && !stdErrors.Is(err, io.EOF) {
+		return nil, errors.New(errors.ErrInvalidRequest, "failed to decode YAML rules", err)
+	}
+
+	return rules, nil
+}
+
+// Categorize applies the first matching rule to transaction. When no rule
+// matches, the transaction is returned unchanged with SourceNone.
+func (e *Engine) Categorize(transaction models.Transaction) Result {
+	for i, rule := range e.rules {
+		reasons, ok := e.match(rule, transaction)
+		if !ok {
+			continue
+		}
+
+		return Result{
+			Transaction: rule.Then.apply(transaction),
+			Source:      SourceRule,
+			Rule:        rule.Name,
+			RuleIndex:   i,
+			Reasons:     reasons,
+		}
+	}
+
+	return Result{Transaction: transaction, Source: SourceNone, RuleIndex: -1}
+}
+
+// Apply categorizes every transaction and returns results in input order.
+func (e *Engine) Apply(transactions []models.Transaction) []Result {
+	results := make([]Result, len(transactions))
+	for i, transaction := range transactions {
+		results[i] = e.Categorize(transaction)
+	}
+
+	return results
+}
+
+// ApplyWithFallback categorizes transactions with rules and sends the
+// transactions that no rule matched to suggester in a single batch. Suggested
+// tags, merchants, and payees fill only empty fields. When the suggestion
+// request fails, the rule results are returned together with the error.
+func (e *Engine) ApplyWithFallback(ctx context.Context, suggester Suggester, transactions []models.Transaction) ([]Result, error) {
+	results := e.Apply(transactions)
+
+	var unmatched []int
+	for i, result := range results {
+		if result.Source == SourceNone {
+			unmatched = append(unmatched, i)
+		}
+	}
+	if len(unmatched) == 0 {
+		return results, nil
+	}
+
+	batch := make([]models.Transaction, len(unmatched))
+	for i, index := range unmatched {
+		batch[i] = results[index].Transaction
+	}
+	suggestions, err := suggester.SuggestBatch(ctx, batch)
+	if err != nil {
+		return results, err
+	}
+	if len(suggestions) != len(batch) {
+		return results, errors.New(
+			errors.ErrInvalidRequest,
+			fmt.Sprintf("suggester returned %d results for %d transactions", len(suggestions), len(batch)),
+			nil,
+		)
+	}
+
+	for i, index := range unmatched {
+		results[index].Transaction = mergeSuggestion(results[index].Transaction, suggestions[i])
+		results[index].Source = SourceSuggest
+	}
+
+	return results, nil
+}
+
+func (e *Engine) match(rule compiledRule, transaction models.Transaction) ([]string, bool) {
+	var reasons []string
+	when := rule.When
+
+	if rule.payee != nil {
+		switch {
+		case transaction.OriginalPayee != "" && rule.payee.MatchString(transaction.OriginalPayee):
+			reasons = append(reasons, fmt.Sprintf("original payee %q matches %q", transaction.OriginalPayee, when.Payee))
+		case transaction.Payee != "" && rule.payee.MatchString(transaction.Payee):
+			reasons = append(reasons, fmt.Sprintf("payee %q matches %q", transaction.Payee, when.Payee))
+		default:
+			return nil, false
+		}
+	}
+
+	if len(when.MCC) > 0 {
+		if transaction.Merchant == nil {
+			return nil, false
+		}
+		merchant, ok := e.merchants[*transaction.Merchant]
+		if !ok || merchant.MCC == nil || !slices.Contains(when.MCC, *merchant.MCC) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("merchant MCC %d is listed", *merchant.MCC))
+	}
+
+	if when.MinAmount != nil || when.MaxAmount != nil {
+		amount := transaction.Income
+		if transaction.Outcome > 0 {
+			amount = transaction.Outcome
+		}
+		if when.MinAmount != nil && amount < *when.MinAmount || when.MaxAmount != nil && amount > *when.MaxAmount {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("amount %s is within range", strconv.FormatFloat(amount, 'f', -1, 64)))
+	}
+
+	if when.Account != "" {
+		if transaction.IncomeAccount != when.Account &&
+			(transaction.OutcomeAccount == nil || *transaction.OutcomeAccount != when.Account) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("account is %s", when.Account))
+	}
+
+	return reasons, true
+}
+
+func (a Action) apply(transaction models.Transaction) models.Transaction {
+	if len(a.Tag) > 0 {
+		transaction.Tag = slices.Clone(a.Tag)
+	}
+	if a.Merchant != "" {
+		merchant := a.Merchant
+		transaction.Merchant = &merchant
+	}
+	if a.Payee != "" {
+		transaction.Payee = a.Payee
+	}
+
+	return transaction
+}
+
+func mergeSuggestion(transaction models.Transaction, suggestion models.Transaction) models.Transaction {
+	if len(transaction.Tag) == 0 && len(suggestion.Tag) > 0 {
+		transaction.Tag = slices.Clone(suggestion.Tag)
+	}
+	if transaction.Merchant == nil && suggestion.Merchant != nil {
+		merchant := *suggestion.Merchant
+		transaction.Merchant = &merchant
+	}
+	if transaction.Payee == "" && suggestion.Payee != "" {
+		transaction.Payee = suggestion.Payee
+	}
+
+	return transaction
+}
@@ -0,0 +1,186 @@
+package rules_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"strings"
+	"testing"
+
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/rules"
+	"github.com/stretchr/testify/require"
+)
+
+type suggesterFunc func(context.Context, []models.Transaction) ([]models.Transaction, error)
+
+func (f suggesterFunc) SuggestBatch(ctx context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
+	return f(ctx, transactions)
+}
+
+func testRules() []rules.Rule {
+	minAmount := 1000.0
+
+	return []rules.Rule{
+		{
+			Name: "coffee",
+			When: rules.Condition{Payee: `starbucks|costa`},
+			Then: rules.Action{Tag: []string{"tag-coffee"}, Payee: "Coffee"},
+		},
+		{
+			Name: "groceries",
+			When: rules.Condition{MCC: []int{5411}, MinAmount: &minAmount},
+			Then: rules.Action{Tag: []string{"tag-groceries"}, Merchant: "merchant-grocery"},
+		},
+		{
+			Name: "cash",
+			When: rules.Condition{Account: "wallet"},
+			Then: rules.Action{Tag: []string{"tag-cash"}},
+		},
+	}
+}
+
+func testMerchants() []models.Merchant {
+	mcc := 5411
+
+	return []models.Merchant{{ID: "store", MCC: &mcc}}
+}
+
+func TestEngineCategorize(t *testing.T) {
+	engine, err := rules.NewEngine(testRules(), testMerchants())
+	require.NoError(t, err)
+	store := "store"
+
+	t.Run("matches payee expression case-insensitively", func(t *testing.T) {
+		result := engine.Categorize(models.Transaction{OriginalPayee: "STARBUCKS #12", Outcome: 300})
+
+		require.Equal(t, rules.SourceRule, result.Source)
+		require.Equal(t, "coffee", result.Rule)
+		require.Equal(t, 0, result.RuleIndex)
+		require.Equal(t, []string{"tag-coffee"}, result.Transaction.Tag)
+		require.Equal(t, "Coffee", result.Transaction.Payee)
+		require.Equal(t, []string{`original payee "STARBUCKS #12" matches "starbucks|costa"`}, result.Reasons)
+	})
+
+	t.Run("combines MCC and amount conditions", func(t *testing.T) {
+		result := engine.Categorize(models.Transaction{Merchant: &store, Outcome: 1500})
+
+		require.Equal(t, "groceries", result.Rule)
+		require.Equal(t, "merchant-grocery", *result.Transaction.Merchant)
+		require.Len(t, result.Reasons, 2)
+
+		result = engine.Categorize(models.Transaction{Merchant: &store, Outcome: 500})
+		require.Equal(t, rules.SourceNone, result.Source)
+		require.Equal(t, -1, result.RuleIndex)
+	})
+
+	t.Run("matches either account side", func(t *testing.T) {
+		wallet := "wallet"
+		result := engine.Categorize(models.Transaction{IncomeAccount: "card", OutcomeAccount: &wallet})
+
+		require.Equal(t, "cash", result.Rule)
+	})
+}
+
+func TestNewEngineValidatesRules(t *testing.T) {
+	minAmount, maxAmount := 10.0, 1.0
+	tests := []struct {
+		name string
+		rule rules.Rule
+	}{
+		{name: "no conditions", rule: rules.Rule{Then: rules.Action{Payee: "x"}}},
+		{name: "no actions", rule: rules.Rule{When: rules.Condition{Payee: "x"}}},
+		{name: "invalid expression", rule: rules.Rule{When: rules.Condition{Payee: "("}, Then: rules.Action{Payee: "x"}}},
+		{
+			name: "inverted amount range",
+			rule: rules.Rule{
+				When: rules.Condition{MinAmount: &minAmount, MaxAmount: &maxAmount},
+				Then: rules.Action{Payee: "x"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rules.NewEngine([]rules.Rule{tt.rule}, nil)
+
+			var sdkErr *sdkerrors.Error
+			require.ErrorAs(t, err, &sdkErr)
+			require.Equal(t, sdkerrors.ErrInvalidRequest, sdkErr.Code)
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	want := []rules.Rule{{
+		Name: "taxi",
+		When: rules.Condition{Payee: "uber", MCC: []int{4121}},
+		Then: rules.Action{Tag: []string{"tag-taxi"}},
+	}}
+
+	fromJSON, err := rules.LoadJSON(strings.NewReader(
+		`[{"name":"taxi","when":{"payee":"uber","mcc":[4121]},"then":{"tag":["tag-taxi"]}}]`,
+	))
+	require.NoError(t, err)
+	require.Equal(t, want, fromJSON)
+
+	fromYAML, err := rules.LoadYAML(strings.NewReader(`
+- name: taxi
+  when:
+    payee: uber
+    mcc: [4121]
+  then:
+    tag: [tag-taxi]
+`))
+	require.NoError(t, err)
+	require.Equal(t, want, fromYAML)
+
+	_, err = rules.LoadJSON(strings.NewReader(`[{"name":"taxi","unknown":true}]`))
+	require.Error(t, err)
+	_, err = rules.LoadYAML(strings.NewReader("- name: taxi\n  unknown: true\n"))
+	require.Error(t, err)
+}
+
+func TestApplyWithFallback(t *testing.T) {
+	engine, err := rules.NewEngine(testRules(), testMerchants())
+	require.NoError(t, err)
+	suggested := "merchant-bakery"
+
+	var requested []models.Transaction
+	suggester := suggesterFunc(func(_ context.Context, transactions []models.Transaction) ([]models.Transaction, error) {
+		requested = transactions
+		return []models.Transaction{{Payee: "Bakery", Tag: []string{"tag-food"}, Merchant: &suggested}}, nil
+	})
+
+	results, err := engine.ApplyWithFallback(context.Background(), suggester, []models.Transaction{
+		{OriginalPayee: "Costa Coffee"},
+		{OriginalPayee: "BAKERY 12", Tag: []string{"tag-kept"}},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, requested, 1)
+	require.Equal(t, "BAKERY 12", requested[0].OriginalPayee)
+	require.Equal(t, rules.SourceRule, results[0].Source)
+	require.Equal(t, rules.SourceSuggest, results[1].Source)
+	require.Equal(t, []string{"tag-kept"}, results[1].Transaction.Tag)
+	require.Equal(t, "Bakery", results[1].Transaction.Payee)
+	require.Equal(t, "merchant-bakery", *results[1].Transaction.Merchant)
+}
+
+func TestApplyWithFallbackReturnsRuleResultsOnError(t *testing.T) {
+	engine, err := rules.NewEngine(testRules(), nil)
+	require.NoError(t, err)
+	cause := stdErrors.New("suggest failed")
+	suggester := suggesterFunc(func(context.Context, []models.Transaction) ([]models.Transaction, error) {
+		return nil, cause
+	})
+
+	results, err := engine.ApplyWithFallback(context.Background(), suggester, []models.Transaction{
+		{OriginalPayee: "Starbucks"},
+		{OriginalPayee: "Unknown"},
+	})
+
+	require.ErrorIs(t, err, cause)
+	require.Equal(t, rules.SourceRule, results[0].Source)
+	require.Equal(t, rules.SourceNone, results[1].Source)
+}
@@ -0,0 +1,329 @@
+// Package schedule computes the payment schedules of loan and deposit
+// accounts from the terms ZenMoney stores on them.
+//
+// A loan is repaid with equal annuity payments when Account.Capitalization is
+// true and with differentiated payments, equal principal parts plus interest
+// on the remaining balance, otherwise. A deposit accrues interest every payoff
+// period; with capitalization the interest is added to the balance, without it
+// the interest is paid out.
+//
+//	s, err := schedule.New(account)
+//	if err != nil {
+//		return err
+//	}
+//	left := s.Remaining(time.Now())
+//	fmt.Println(left.Payments, "payments until", left.PayoffDate.Format(time.DateOnly))
+//
+// Interest for a period is the annual Percent multiplied by the length of a
+// payoff period in years, with months counted as 1/12 of a year and days as
+// 1/365. All periods of a schedule use the same rate, and amounts are rounded
+// to two decimal places with the last row absorbing the rounding difference.
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Method is the way a schedule repays a loan or accrues deposit interest.
+type Method string
+
+const (
+	// MethodAnnuity repays a loan with equal payments.
+	MethodAnnuity Method = "annuity"
+	// MethodDifferentiated repays equal principal parts plus interest on the
+	// remaining balance.
+	MethodDifferentiated Method = "differentiated"
+	// MethodCapitalized adds deposit interest to the balance.
+	MethodCapitalized Method = "capitalized"
+	// MethodSimple pays deposit interest out and keeps the balance.
+	MethodSimple Method = "simple"
+)
+
+// Row is one payoff date of a schedule.
+type Row struct {
+	Date time.Time
+
+	// Interest is the interest charged on a loan or accrued on a deposit for
+	// the period that ends at Date.
+	Interest float64
+
+	// Principal is the part of a loan repaid at Date. It is zero for deposits.
+	Principal float64
+
+	// Payment is the amount paid at Date: Principal plus Interest for loans,
+	// and the interest paid out for deposits without capitalization.
+	Payment float64
+
+	// Balance is the loan debt or deposit balance after the row.
+	Balance float64
+}
+
+// Schedule is the amortization table of a loan or the accrual table of a
+// deposit.
+type Schedule struct {
+	Type   models.AccountType
+	Method Method
+
+	// Principal is the loan amount or initial deposit, taken as the absolute
+	// value of Account.StartBalance.
+	Principal float64
+
+	// Percent is the annual interest rate in percent.
+	Percent float64
+
+	StartDate time.Time
+	EndDate   time.Time
+
+	Rows          []Row
+	TotalInterest float64
+}
+
+// Remaining describes the part of a schedule after a point in time.
+type Remaining struct {
+	// Payments is the number of rows dated after the point in time.
+	Payments int
+
+	// Balance is the scheduled loan debt or deposit balance at the point in
+	// time.
+	Balance float64
+
+	// PayoffDate is the date of the last row: the date the loan is repaid or
+	// the deposit matures.
+	PayoffDate time.Time
+}
+
+// New computes the schedule of a loan or deposit account. It returns an
+// ErrInvalidRequest error when the account is of another type or its terms
+// are missing or invalid.
+func New(account models.Account) (*Schedule, error) {
+	t, err := parseTerms(account)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schedule{
+		Type:      account.AccountType(),
+		Principal: t.principal,
+		Percent:   t.percent,
+		StartDate: t.start,
+		EndDate:   t.end,
+	}
+	dates := t.paymentDates()
+	rate := t.percent / 100 * t.periodYears()
+
+	switch {
+	case s.Type == models.AccountTypeLoan && *account.Capitalization:
+		s.Method = MethodAnnuity
+		s.Rows = annuity(t.principal, rate, dates)
+	case s.Type == models.AccountTypeLoan:
+		s.Method = MethodDifferentiated
+		s.Rows = differentiated(t.principal, rate, dates)
+	case *account.Capitalization:
+		s.Method = MethodCapitalized
+		s.Rows = capitalized(t.principal, rate, dates)
+	default:
+		s.Method = MethodSimple
+		s.Rows = simple(t.principal, rate, dates)
+	}
+	for _, row := range s.Rows {
+		s.TotalInterest += row.Interest
+	}
+	s.TotalInterest = finance.Round(s.TotalInterest)
+
+	return s, nil
+}
+
+// Remaining returns the number of payments left after at, the scheduled
+// balance at that time, and the payoff date.
+func (s *Schedule) Remaining(at time.Time) Remaining {
+	result := Remaining{Balance: s.Principal, PayoffDate: s.EndDate}
+	for _, row := range s.Rows {
+		if row.Date.After(at) {
+			result.Payments++
+		} else {
+			result.Balance = row.Balance
+		}
+	}
+	if len(s.Rows) > 0 {
+		result.PayoffDate = s.Rows[len(s.Rows)-1].Date
+	}
+
+	return result
+}
+
+func annuity(principal float64, rate float64, dates []time.Time) []Row {
+	n := float64(len(dates))
+	payment := principal / n
+	if rate > 0 {
+		payment = principal * rate / (1 - math.Pow(1+rate, -n))
+	}
+	payment = finance.Round(payment)
+
+	rows := make([]Row, len(dates))
+	balance := principal
+	for i, date := range dates {
+		interest := finance.Round(balance * rate)
+		part := payment - interest
+		if i == len(dates)-1 {
+			part = balance
+		}
+		balance = finance.Round(balance - part)
+		rows[i] = Row{Date: date, Interest: interest, Principal: finance.Round(part), Payment: finance.Round(part + interest), Balance: balance}
+	}
+
+	return rows
+}
+
+func differentiated(principal float64, rate float64, dates []time.Time) []Row {
+	part := finance.Round(principal / float64(len(dates)))
+
+	rows := make([]Row, len(dates))
+	balance := principal
+	for i, date := range dates {
+		interest := finance.Round(balance * rate)
+		if i == len(dates)-1 {
+			part = balance
+		}
+		balance = finance.Round(balance - part)
+		rows[i] = Row{Date: date, Interest: interest, Principal: part, Payment: finance.Round(part + interest), Balance: balance}
+	}
+
+	return rows
+}
+
+func capitalized(principal float64, rate float64, dates []time.Time) []Row {
+	rows := make([]Row, len(dates))
+	balance := principal
+	for i, date := range dates {
+		interest := finance.Round(balance * rate)
+		balance = finance.Round(balance + interest)
+		rows[i] = Row{Date: date, Interest: interest, Balance: balance}
+	}
+
+	return rows
+}
+
+func simple(principal float64, rate float64, dates []time.Time) []Row {
+	interest := finance.Round(principal * rate)
+
+	rows := make([]Row, len(dates))
+	for i, date := range dates {
+		rows[i] = Row{Date: date, Interest: interest, Payment: interest, Balance: principal}
+	}
+
+	return rows
+}
+
+// terms are the validated loan or deposit terms of an account.
+type terms struct {
+	principal float64
+	percent   float64
+	start     time.Time
+	end       time.Time
+
+	// payoffInterval is empty when the whole amount is settled at the end of
+	// the term.
+	payoffInterval models.Interval
+	payoffStep     int
+
+	termInterval models.Interval
+	termLength   int
+}
+
+func parseTerms(account models.Account) (terms, error) {
+	if accountType := account.AccountType(); accountType != models.AccountTypeLoan && accountType != models.AccountTypeDeposit {
+		return terms{}, invalid(fmt.Sprintf("account type %q has no schedule", account.Type), nil)
+	}
+	if account.StartBalance == nil {
+		return terms{}, invalid("start balance is required", nil)
+	}
+	if account.Percent == nil || *account.Percent < 0 {
+		return terms{}, invalid("percent must be set and not negative", nil)
+	}
+	if account.Capitalization == nil {
+		return terms{}, invalid("capitalization is required", nil)
+	}
+	if account.StartDate == nil {
+		return terms{}, invalid("start date is required", nil)
+	}
+	start, err := time.Parse(time.DateOnly, *account.StartDate)
+	if err != nil {
+		return terms{}, invalid("start date is invalid", err)
+	}
+	if account.EndDateOffset == nil || *account.EndDateOffset <= 0 {
+		return terms{}, invalid("end date offset must be positive", nil)
+	}
+	if !account.TermInterval().IsValid() {
+		return terms{}, invalid("end date offset interval is invalid", nil)
+	}
+
+	t := terms{
+		principal:    math.Abs(*account.StartBalance),
+		percent:      *account.Percent,
+		start:        start,
+		termInterval: account.TermInterval(),
+		termLength:   int(*account.EndDateOffset),
+	}
+	t.end = finance.AddInterval(start, t.termInterval, t.termLength)
+	if account.PayoffInterval != nil {
+		if !account.PaymentInterval().IsValid() {
+			return terms{}, invalid("payoff interval is invalid", nil)
+		}
+		t.payoffInterval = account.PaymentInterval()
+		t.payoffStep = 1
+		if account.PayoffStep != nil && *account.PayoffStep > 0 {
+			t.payoffStep = int(*account.PayoffStep)
+		}
+	}
+
+	return t, nil
+}
+
+// paymentDates returns the payoff dates after the start date. The last date is
+// always the end of the term.
+func (t terms) paymentDates() []time.Time {
+	if t.payoffInterval == "" {
+		return []time.Time{t.end}
+	}
+
+	var dates []time.Time
+	for k := 1; ; k++ {
+		date := finance.AddInterval(t.start, t.payoffInterval, k*t.payoffStep)
+		if !date.Before(t.end) {
+			return append(dates, t.end)
+		}
+		dates = append(dates, date)
+	}
+}
+
+// periodYears returns the length of a payoff period in years.
+func (t terms) periodYears() float64 {
+	if t.payoffInterval == "" {
+		return years(t.termInterval, t.termLength)
+	}
+
+	return years(t.payoffInterval, t.payoffStep)
+}
+
+func years(interval models.Interval, n int) float64 {
+	switch interval {
+	case models.IntervalDay:
+		return float64(n) / 365
+	case models.IntervalWeek:
+		return float64(7*n) / 365
+	case models.IntervalMonth:
+		return float64(n) / 12
+	default:
+		return float64(n)
+	}
+}
+
+func invalid(message string, cause error) error {
+	return errors.New(errors.ErrInvalidRequest, message, cause)
+}
@@ -0,0 +1,108 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/schedule"
+	"github.com/stretchr/testify/require"
+)
+
+func account(accountType string, capitalization bool, payoff *string) models.Account {
+	startBalance := 1200.0
+	percent := 12.0
+	startDate := "2024-01-31"
+	offset := int32(12)
+	month := "month"
+
+	return models.Account{
+		Type:                  accountType,
+		StartBalance:          &startBalance,
+		Percent:               &percent,
+		Capitalization:        &capitalization,
+		StartDate:             &startDate,
+		EndDateOffset:         &offset,
+		EndDateOffsetInterval: &month,
+		PayoffInterval:        payoff,
+	}
+}
+
+func date(value string) time.Time {
+	result, _ := time.Parse(time.DateOnly, value)
+	return result
+}
+
+func TestAnnuityLoan(t *testing.T) {
+	month := "month"
+	s, err := schedule.New(account("loan", true, &month))
+	require.NoError(t, err)
+
+	require.Equal(t, schedule.MethodAnnuity, s.Method)
+	require.Len(t, s.Rows, 12)
+	require.Equal(t, date("2024-02-29"), s.Rows[0].Date)
+	require.Equal(t, date("2025-01-31"), s.Rows[11].Date)
+	require.Equal(t, schedule.Row{Date: date("2024-02-29"), Interest: 12, Principal: 94.62, Payment: 106.62, Balance: 1105.38}, s.Rows[0])
+	for _, row := range s.Rows[:11] {
+		require.InDelta(t, 106.62, row.Payment, 0.001)
+	}
+	require.Zero(t, s.Rows[11].Balance)
+	require.InDelta(t, 79.4, s.TotalInterest, 0.1)
+}
+
+func TestDifferentiatedLoan(t *testing.T) {
+	month := "month"
+	s, err := schedule.New(account("loan", false, &month))
+	require.NoError(t, err)
+
+	require.Equal(t, schedule.MethodDifferentiated, s.Method)
+	require.Equal(t, 112.0, s.Rows[0].Payment)
+	require.Equal(t, 101.0, s.Rows[11].Payment)
+	require.Zero(t, s.Rows[11].Balance)
+	require.Equal(t, 78.0, s.TotalInterest)
+}
+
+func TestDeposit(t *testing.T) {
+	month := "month"
+
+	s, err := schedule.New(account("deposit", true, &month))
+	require.NoError(t, err)
+	require.Equal(t, schedule.MethodCapitalized, s.Method)
+	require.Equal(t, 1352.19, s.Rows[11].Balance)
+	require.Zero(t, s.Rows[0].Payment)
+
+	s, err = schedule.New(account("deposit", false, &month))
+	require.NoError(t, err)
+	require.Equal(t, schedule.MethodSimple, s.Method)
+	require.Equal(t, schedule.Row{Date: date("2024-02-29"), Interest: 12, Payment: 12, Balance: 1200}, s.Rows[0])
+	require.Equal(t, 144.0, s.TotalInterest)
+
+	s, err = schedule.New(account("deposit", false, nil))
+	require.NoError(t, err)
+	require.Equal(t, []schedule.Row{{Date: date("2025-01-31"), Interest: 144, Payment: 144, Balance: 1200}}, s.Rows)
+}
+
+func TestRemaining(t *testing.T) {
+	month := "month"
+	s, err := schedule.New(account("loan", false, &month))
+	require.NoError(t, err)
+
+	require.Equal(t, schedule.Remaining{Payments: 12, Balance: 1200, PayoffDate: date("2025-01-31")}, s.Remaining(date("2024-01-31")))
+	require.Equal(t, schedule.Remaining{Payments: 9, Balance: 900, PayoffDate: date("2025-01-31")}, s.Remaining(date("2024-04-30")))
+	require.Equal(t, schedule.Remaining{Payments: 0, Balance: 0, PayoffDate: date("2025-01-31")}, s.Remaining(date("2025-06-01")))
+}
+
+func TestNewRejectsInvalidTerms(t *testing.T) {
+	month := "month"
+	cash := account("cash", false, &month)
+	missing := account("loan", true, &month)
+	missing.StartDate = nil
+
+	for _, acc := range []models.Account{cash, missing} {
+		_, err := schedule.New(acc)
+		var sdkErr *sdkerrors.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, sdkerrors.ErrInvalidRequest, sdkErr.Code)
+	}
+}
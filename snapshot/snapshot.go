@@ -0,0 +1,190 @@
+// Package snapshot compares two full synchronization responses.
+//
+// Compare matches entities of each type by primary key and reports which
+// entities were added, removed, or modified. Modifications list every changed
+// field with its old and new value, which makes the result suitable for audit
+// logs and user notifications. Fields are named by their JSON names, the same
+// names the ZenMoney API uses.
+package snapshot
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/store"
+)
+
+// Kind describes how an entity changed.
+type Kind string
+
+const (
+	Added    Kind = "added"
+	Removed  Kind = "removed"
+	Modified Kind = "modified"
+)
+
+// Options configures Compare.
+type Options struct {
+	// IgnoreFields lists JSON field names that are not compared, for example
+	// "changed" to skip entities whose only difference is the modification
+	// timestamp.
+	IgnoreFields []string
+}
+
+// FieldChange is a difference in one field. Pointer fields are dereferenced,
+// so Old and New hold values or nil.
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// Change describes one added, removed, or modified entity. Old is nil for
+// added entities and New is nil for removed ones; both hold entity values such
+// as models.Transaction. Fields is set only for modified entities.
+type Change struct {
+	EntityType models.EntityType
+	Key        string
+	Kind       Kind
+	Old        any
+	New        any
+	Fields     []FieldChange
+}
+
+// String formats the change for logs, for example
+// "transaction tx-1 modified: outcome 10 -> 12".
+func (c Change) String() string {
+	message := fmt.Sprintf("%s %s %s", c.EntityType, c.Key, c.Kind)
+	if len(c.Fields) == 0 {
+		return message
+	}
+
+	fields := make([]string, len(c.Fields))
+	for i, field := range c.Fields {
+		fields[i] = fmt.Sprintf("%s %s -> %s", field.Field, formatValue(field.Old), formatValue(field.New))
+	}
+
+	return message + ": " + strings.Join(fields, ", ")
+}
+
+// Compare returns the changes that turn before into after. Changes are grouped
+// by entity type in the order of models.Response fields. Within a type,
+// modified and removed entities follow the order of before, and added entities
+// follow the order of after. Entities are matched with the key functions of
+// package store.
+func Compare(before, after models.Response, opts Options) []Change {
+	ignored := make(map[string]bool, len(opts.IgnoreFields))
+	for _, field := range opts.IgnoreFields {
+		ignored[field] = true
+	}
+
+	var changes []Change
+	changes = compareEntities(changes, models.EntityTypeInstrument, before.Instrument, after.Instrument, store.InstrumentKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeCountry, before.Country, after.Country, store.CountryKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeCompany, before.Company, after.Company, store.CompanyKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeUser, before.User, after.User, store.UserKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeAccount, before.Account, after.Account, store.AccountKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeTag, before.Tag, after.Tag, store.TagKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeMerchant, before.Merchant, after.Merchant, store.MerchantKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeBudget, before.Budget, after.Budget, store.BudgetKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeReminder, before.Reminder, after.Reminder, store.ReminderKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeReminderMarker, before.ReminderMarker, after.ReminderMarker, store.ReminderMarkerKey, ignored)
+	changes = compareEntities(changes, models.EntityTypeTransaction, before.Transaction, after.Transaction, store.TransactionKey, ignored)
+
+	return changes
+}
+
+func compareEntities[T any](changes []Change, entityType models.EntityType, before, after []T, key func(T) string, ignored map[string]bool) []Change {
+	current := make(map[string]T, len(after))
+	for _, entity := range after {
+		current[key(entity)] = entity
+	}
+
+	previous := make(map[string]bool, len(before))
+	for _, old := range before {
+		entityKey := key(old)
+		previous[entityKey] = true
+
+		updated, ok := current[entityKey]
+		if !ok {
+			changes = append(changes, Change{EntityType: entityType, Key: entityKey, Kind: Removed, Old: old})
+			continue
+		}
+		if fields := compareFields(reflect.ValueOf(old), reflect.ValueOf(updated), ignored); len(fields) > 0 {
+			changes = append(changes, Change{
+				EntityType: entityType,
+				Key:        entityKey,
+				Kind:       Modified,
+				Old:        old,
+				New:        updated,
+				Fields:     fields,
+			})
+		}
+	}
+
+	for _, entity := range after {
+		if entityKey := key(entity); !previous[entityKey] {
+			previous[entityKey] = true
+			changes = append(changes, Change{EntityType: entityType, Key: entityKey, Kind: Added, New: entity})
+		}
+	}
+
+	return changes
+}
+
+func compareFields(old, updated reflect.Value, ignored map[string]bool) []FieldChange {
+	var fields []FieldChange
+	for i := range old.NumField() {
+		name := fieldName(old.Type().Field(i))
+		if ignored[name] {
+			continue
+		}
+
+		oldValue := fieldValue(old.Field(i))
+		newValue := fieldValue(updated.Field(i))
+		if !reflect.DeepEqual(oldValue, newValue) {
+			fields = append(fields, FieldChange{Field: name, Old: oldValue, New: newValue})
+		}
+	}
+
+	return fields
+}
+
+func fieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+
+	return field.Name
+}
+
+// fieldValue dereferences pointers and treats empty slices as nil so that
+// encoding differences are not reported as changes.
+func fieldValue(value reflect.Value) any {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+		return value.Elem().Interface()
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return nil
+		}
+	}
+
+	return value.Interface()
+}
+
+func formatValue(value any) string {
+	if value == nil {
+		return "null"
+	}
+	if text, ok := value.(string); ok {
+		return fmt.Sprintf("%q", text)
+	}
+
+	return fmt.Sprint(value)
+}
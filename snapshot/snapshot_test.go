@@ -0,0 +1,105 @@
+package snapshot_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	comment := "lunch"
+	before := models.Response{
+		Account: []models.Account{{ID: "acc-1", Title: "Card", Balance: ptr(100.0)}},
+		Transaction: []models.Transaction{
+			{ID: "tx-1", Outcome: 10, Changed: 1, Tag: []string{"tag-food"}},
+			{ID: "tx-2", Outcome: 20, Changed: 1},
+			{ID: "tx-3", Outcome: 30, Changed: 1},
+		},
+	}
+	after := models.Response{
+		Account: []models.Account{{ID: "acc-1", Title: "Card", Balance: ptr(90.0)}},
+		Transaction: []models.Transaction{
+			{ID: "tx-4", Outcome: 40, Changed: 2},
+			{ID: "tx-1", Outcome: 12, Changed: 2, Tag: []string{"tag-food"}, Comment: &comment},
+			{ID: "tx-3", Outcome: 30, Changed: 2},
+		},
+	}
+
+	changes := snapshot.Compare(before, after, snapshot.Options{IgnoreFields: []string{"changed"}})
+
+	require.Equal(t, []snapshot.Change{
+		{
+			EntityType: models.EntityTypeAccount,
+			Key:        "acc-1",
+			Kind:       snapshot.Modified,
+			Old:        before.Account[0],
+			New:        after.Account[0],
+			Fields:     []snapshot.FieldChange{{Field: "balance", Old: 100.0, New: 90.0}},
+		},
+		{
+			EntityType: models.EntityTypeTransaction,
+			Key:        "tx-1",
+			Kind:       snapshot.Modified,
+			Old:        before.Transaction[0],
+			New:        after.Transaction[1],
+			Fields: []snapshot.FieldChange{
+				{Field: "outcome", Old: 10.0, New: 12.0},
+				{Field: "comment", Old: nil, New: "lunch"},
+			},
+		},
+		{EntityType: models.EntityTypeTransaction, Key: "tx-2", Kind: snapshot.Removed, Old: before.Transaction[1]},
+		{EntityType: models.EntityTypeTransaction, Key: "tx-4", Kind: snapshot.Added, New: after.Transaction[0]},
+	}, changes)
+	require.Equal(t, `transaction tx-1 modified: outcome 10 -> 12, comment null -> "lunch"`, changes[1].String())
+	require.Equal(t, "transaction tx-2 removed", changes[2].String())
+}
+
+func TestCompareReportsIgnoredFieldsByDefault(t *testing.T) {
+	changes := snapshot.Compare(
+		models.Response{Tag: []models.Tag{{ID: "tag-1", Changed: 1}}},
+		models.Response{Tag: []models.Tag{{ID: "tag-1", Changed: 2}}},
+		snapshot.Options{},
+	)
+
+	require.Len(t, changes, 1)
+	require.Equal(t, []snapshot.FieldChange{{Field: "changed", Old: int64(1), New: int64(2)}}, changes[0].Fields)
+}
+
+func TestCompareMatchesBudgetsByCompositeKey(t *testing.T) {
+	food := "tag-food"
+	changes := snapshot.Compare(
+		models.Response{Budget: []models.Budget{
+			{User: 1, Date: "2024-01-01", Outcome: 100},
+			{User: 1, Date: "2024-01-01", Tag: &food, Outcome: 50},
+		}},
+		models.Response{Budget: []models.Budget{
+			{User: 1, Date: "2024-01-01", Tag: &food, Outcome: 60},
+			{User: 1, Date: "2024-01-01", Outcome: 100},
+		}},
+		snapshot.Options{},
+	)
+
+	require.Len(t, changes, 1)
+	require.Equal(t, "1/2024-01-01/tag-food", changes[0].Key)
+	require.Equal(t, []snapshot.FieldChange{{Field: "outcome", Old: 50.0, New: 60.0}}, changes[0].Fields)
+}
+
+func TestCompareIdenticalFixture(t *testing.T) {
+	payload, err := os.ReadFile("../example.json")
+	require.NoError(t, err)
+	var response models.Response
+	require.NoError(t, json.Unmarshal(payload, &response))
+	var copied models.Response
+	require.NoError(t, json.Unmarshal(payload, &copied))
+
+	require.Empty(t, snapshot.Compare(response, copied, snapshot.Options{}))
+	require.NotEmpty(t, snapshot.Compare(models.Response{}, response, snapshot.Options{}))
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,262 @@
+// Package file keeps a local replica of ZenMoney data in a single compressed
+// snapshot file.
+//
+// A Store loads the snapshot on Open, merges synchronization responses into
+// it, and checkpoints the full entity state together with the server
+// timestamp after every change. Checkpoints are written to a temporary file
+// that is renamed over the snapshot, so a crash never leaves a partially
+// written file behind. Because the cursor survives restarts, Sync performs a
+// full synchronization only on the first run and incremental ones afterwards.
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/store"
+)
+
+// FormatVersion is the snapshot format written by this package. Older
+// versions are migrated when they are loaded.
+const FormatVersion = 1
+
+const formatName = "zenmoney-snapshot"
+
+// migrations upgrade a snapshot envelope from the version used as the key to
+// the next version.
+var migrations = map[int]func(envelope) (envelope, error){}
+
+// envelope is the on-disk layout of a snapshot before compression.
+type envelope struct {
+	Format  string          `json:"format"`
+	Version int             `json:"version"`
+	SavedAt int64           `json:"savedAt"` // Unix timestamp
+	State   json.RawMessage `json:"state"`
+}
+
+// Syncer performs synchronization requests. *api.Client implements Syncer.
+type Syncer interface {
+	FullSync(ctx context.Context) (models.Response, error)
+	SyncSince(ctx context.Context, lastSync time.Time) (models.Response, error)
+}
+
+// Store is a file-backed replica. A Store is safe for concurrent use by one
+// process; it does not lock the file against other processes.
+type Store struct {
+	path  string
+	clock models.Clock
+
+	mu    sync.RWMutex
+	state models.Response
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithClock sets the clock that stamps checkpoints. The default is
+// models.SystemClock, and a nil clock restores it.
+func WithClock(clock models.Clock) Option {
+	return func(s *Store) {
+		s.clock = clock
+	}
+}
+
+// Open loads the snapshot at path. A missing file yields an empty store whose
+// ServerTimestamp is zero. Open returns an *errors.Error with ErrStorage when
+// the file cannot be read, is not a snapshot, or was written by a newer
+// version of this package.
+func Open(path string, opts ...Option) (*Store, error) {
+	s := &Store{path: path}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.clock == nil {
+		s.clock = models.SystemClock{}
+	}
+
+	file, err := os.Open(path)
+	if stdErrors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, errors.New(errors.ErrStorage, "failed to open snapshot", err)
+	}
+	defer file.Close()
+
+	state, err := decode(file)
+	if err != nil {
+		return nil, err
+	}
+	s.state = state
+
+	return s, nil
+}
+
+// Path returns the snapshot file path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// ServerTimestamp returns the server timestamp of the stored state, or zero
+// when nothing has been synchronized.
+func (s *Store) ServerTimestamp() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.state.ServerTimestamp
+}
+
+// State returns the stored entity state. The returned value shares slices
+// with the store and must not be modified.
+func (s *Store) State() models.Response {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.state
+}
+
+// Apply merges diff into the stored state and writes a checkpoint. When the
+// checkpoint fails, the in-memory state is left unchanged.
+func (s *Store) Apply(diff models.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := store.Merge(s.state, diff)
+	if err := s.write(next); err != nil {
+		return err
+	}
+	s.state = next
+
+	return nil
+}
+
+// Sync fetches changes with syncer and applies them. It calls FullSync when
+// the store is empty and SyncSince with the stored server timestamp
+// otherwise. The response is returned so callers can inspect the changes.
+func (s *Store) Sync(ctx context.Context, syncer Syncer) (models.Response, error) {
+	var response models.Response
+	var err error
+	if lastSync := s.ServerTimestamp(); lastSync == 0 {
+		response, err = syncer.FullSync(ctx)
+	} else {
+		response, err = syncer.SyncSince(ctx, time.Unix(lastSync, 0))
+	}
+	if err != nil {
+		return models.Response{}, err
+	}
+
+	if err := s.Apply(response); err != nil {
+		return models.Response{}, err
+	}
+
+	return response, nil
+}
+
+func (s *Store) write(state models.Response) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return errors.New(errors.ErrStorage, "failed to encode snapshot state", err)
+	}
+
+	directory := filepath.Dir(s.path)
+	temporary, err := os.CreateTemp(directory, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return errors.New(errors.ErrStorage, "failed to create temporary snapshot", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = temporary.Close()
+			_ = os.Remove(temporary.Name())
+		}
+	}()
+
+	compressed := gzip.NewWriter(temporary)
+	if err := json.NewEncoder(compressed).Encode(envelope{
+		Format:  formatName,
+		Version: FormatVersion,
+		SavedAt: s.clock.Now().Unix(),
+		State:   payload,
+	}); err != nil {
+		return errors.New(errors.ErrStorage, "failed to write snapshot", err)
+	}
+	if err := compressed.Close(); err != nil {
+		return errors.New(errors.ErrStorage, "failed to write snapshot", err)
+	}
+	if err := temporary.Sync(); err != nil {
+		return errors.New(errors.ErrStorage, "failed to flush snapshot", err)
+	}
+	if err := temporary.Close(); err != nil {
+		return errors.New(errors.ErrStorage, "failed to close snapshot", err)
+	}
+	if err := os.Rename(temporary.Name(), s.path); err != nil {
+		return errors.New(errors.ErrStorage, "failed to replace snapshot", err)
+	}
+	committed = true
+	syncDirectory(directory)
+
+	return nil
+}
+
+func decode(r io.Reader) (models.Response, error) {
+	compressed, err := gzip.NewReader(r)
+	if err != nil {
+		return models.Response{}, errors.New(errors.ErrStorage, "snapshot is not gzip-compressed", err)
+	}
+	defer compressed.Close()
+
+	var snapshot envelope
+	if err := json.NewDecoder(compressed).Decode(&snapshot); err != nil {
+		return models.Response{}, errors.New(errors.ErrStorage, "failed to decode snapshot", err)
+	}
+	if snapshot.Format != formatName {
+		return models.Response{}, errors.New(errors.ErrStorage, "file is not a ZenMoney snapshot", nil)
+	}
+	if snapshot.Version > FormatVersion {
+		return models.Response{}, errors.New(errors.ErrStorage,
+			fmt.Sprintf("snapshot version %d is newer than supported version %d", snapshot.Version, FormatVersion), nil)
+	}
+	for snapshot.Version < FormatVersion {
+		migrate, ok := migrations[snapshot.Version]
+		if !ok {
+			return models.Response{}, errors.New(errors.ErrStorage,
+				fmt.Sprintf("no migration from snapshot version %d", snapshot.Version), nil)
+		}
+		from := snapshot.Version
+		if snapshot, err = migrate(snapshot); err != nil {
+			return models.Response{}, errors.New(errors.ErrStorage,
+				fmt.Sprintf("failed to migrate snapshot from version %d", from), err)
+		}
+		if snapshot.Version <= from {
+			return models.Response{}, errors.New(errors.ErrStorage,
+				fmt.Sprintf("migration from snapshot version %d did not advance the version", from), nil)
+		}
+	}
+
+	var state models.Response
+	if err := json.Unmarshal(snapshot.State, &state); err != nil {
+		return models.Response{}, errors.New(errors.ErrStorage, "failed to decode snapshot state", err)
+	}
+
+	return state, nil
+}
+
+// syncDirectory flushes the directory entry created by the rename. Errors are
+// ignored because not every platform supports syncing directories.
+func syncDirectory(directory string) {
+	if dir, err := os.Open(directory); err == nil {
+		_ = dir.Sync()
+		_ = dir.Close()
+	}
+}
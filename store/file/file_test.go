@@ -0,0 +1,190 @@
+package file_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/store/file"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSyncer struct {
+	full      models.Response
+	since     models.Response
+	fullCalls int
+	lastSync  []time.Time
+}
+
+func (f *fakeSyncer) FullSync(context.Context) (models.Response, error) {
+	f.fullCalls++
+	return f.full, nil
+}
+
+func (f *fakeSyncer) SyncSince(_ context.Context, lastSync time.Time) (models.Response, error) {
+	f.lastSync = append(f.lastSync, lastSync)
+	return f.since, nil
+}
+
+func TestStoreRoundTripsFixture(t *testing.T) {
+	payload, err := os.ReadFile("../../example.json")
+	require.NoError(t, err)
+	var response models.Response
+	require.NoError(t, json.Unmarshal(payload, &response))
+	path := filepath.Join(t.TempDir(), "snapshot.json.gz")
+
+	store, err := file.Open(path)
+	require.NoError(t, err)
+	require.Zero(t, store.ServerTimestamp())
+	require.NoError(t, store.Apply(response))
+
+	reopened, err := file.Open(path)
+	require.NoError(t, err)
+	response.Deletion = nil
+	require.Equal(t, response, reopened.State())
+	require.Equal(t, response.ServerTimestamp, reopened.ServerTimestamp())
+}
+
+func TestStoreSyncUsesCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json.gz")
+	syncer := &fakeSyncer{
+		full: models.Response{
+			ServerTimestamp: 100,
+			Transaction:     []models.Transaction{{ID: "tx-1", Outcome: 10}, {ID: "tx-2", Outcome: 20}},
+		},
+		since: models.Response{
+			ServerTimestamp: 200,
+			Transaction:     []models.Transaction{{ID: "tx-3", Outcome: 30}},
+			Deletion:        []models.Deletion{{ID: "tx-1", Object: string(models.EntityTypeTransaction), Stamp: 150}},
+		},
+	}
+	ctx := context.Background()
+
+	store, err := file.Open(path)
+	require.NoError(t, err)
+	_, err = store.Sync(ctx, syncer)
+	require.NoError(t, err)
+	require.Equal(t, 1, syncer.fullCalls)
+	require.Empty(t, syncer.lastSync)
+
+	store, err = file.Open(path)
+	require.NoError(t, err)
+	changes, err := store.Sync(ctx, syncer)
+	require.NoError(t, err)
+	require.Equal(t, syncer.since, changes)
+	require.Equal(t, 1, syncer.fullCalls)
+	require.Equal(t, []time.Time{time.Unix(100, 0)}, syncer.lastSync)
+
+	require.Equal(t, int64(200), store.ServerTimestamp())
+	require.Equal(t, []models.Transaction{{ID: "tx-2", Outcome: 20}, {ID: "tx-3", Outcome: 30}}, store.State().Transaction)
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestStoreStampsCheckpointsWithClock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json.gz")
+	savedAt := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+	store, err := file.Open(path, file.WithClock(fixedClock(savedAt)))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Apply(models.Response{ServerTimestamp: 100}))
+
+	snapshot, err := os.Open(path)
+	require.NoError(t, err)
+	defer snapshot.Close()
+	compressed, err := gzip.NewReader(snapshot)
+	require.NoError(t, err)
+	var envelope struct {
+		SavedAt int64 `json:"savedAt"`
+	}
+	require.NoError(t, json.NewDecoder(compressed).Decode(&envelope))
+	require.Equal(t, savedAt.Unix(), envelope.SavedAt)
+}
+
+func TestStoreLeavesNoTemporaryFiles(t *testing.T) {
+	directory := t.TempDir()
+	store, err := file.Open(filepath.Join(directory, "snapshot.json.gz"))
+	require.NoError(t, err)
+
+	for timestamp := int64(1); timestamp <= 3; timestamp++ {
+		require.NoError(t, store.Apply(models.Response{ServerTimestamp: timestamp}))
+	}
+
+	entries, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "snapshot.json.gz", entries[0].Name())
+}
+
+func TestStoreKeepsStateWhenCheckpointFails(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "missing")
+	store, err := file.Open(filepath.Join(directory, "snapshot.json.gz"))
+	require.NoError(t, err)
+
+	err = store.Apply(models.Response{ServerTimestamp: 100})
+
+	var sdkErr *sdkerrors.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, sdkerrors.ErrStorage, sdkErr.Code)
+	require.Zero(t, store.ServerTimestamp())
+}
+
+func TestOpenRejectsInvalidSnapshots(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(t *testing.T, path string)
+	}{
+		{
+			name: "not compressed",
+			write: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte(`{"format":"zenmoney-snapshot"}`), 0o600))
+			},
+		},
+		{
+			name: "foreign format",
+			write: func(t *testing.T, path string) {
+				writeCompressed(t, path, `{"format":"other","version":1,"state":{}}`)
+			},
+		},
+		{
+			name: "newer version",
+			write: func(t *testing.T, path string) {
+				writeCompressed(t, path, `{"format":"zenmoney-snapshot","version":99,"state":{}}`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "snapshot.json.gz")
+			tt.write(t, path)
+
+			_, err := file.Open(path)
+
+			var sdkErr *sdkerrors.Error
+			require.ErrorAs(t, err, &sdkErr)
+			require.Equal(t, sdkerrors.ErrStorage, sdkErr.Code)
+		})
+	}
+}
+
+func writeCompressed(t *testing.T, path string, content string) {
+	t.Helper()
+	output, err := os.Create(path)
+	require.NoError(t, err)
+	compressed := gzip.NewWriter(output)
+	_, err = compressed.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, compressed.Close())
+	require.NoError(t, output.Close())
+}
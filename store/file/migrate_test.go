@@ -0,0 +1,45 @@
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMigratesOlderSnapshots(t *testing.T) {
+	previous := migrations
+	t.Cleanup(func() { migrations = previous })
+	migrations = map[int]func(envelope) (envelope, error){
+		0: func(snapshot envelope) (envelope, error) {
+			var legacy struct {
+				Timestamp int64 `json:"timestamp"`
+			}
+			if err := json.Unmarshal(snapshot.State, &legacy); err != nil {
+				return envelope{}, err
+			}
+			state, err := json.Marshal(map[string]int64{"serverTimestamp": legacy.Timestamp})
+			if err != nil {
+				return envelope{}, err
+			}
+			snapshot.Version = 1
+			snapshot.State = state
+			return snapshot, nil
+		},
+	}
+
+	var buffer bytes.Buffer
+	compressed := gzip.NewWriter(&buffer)
+	require.NoError(t, json.NewEncoder(compressed).Encode(envelope{
+		Format:  formatName,
+		Version: 0,
+		State:   json.RawMessage(`{"timestamp":42}`),
+	}))
+	require.NoError(t, compressed.Close())
+
+	state, err := decode(&buffer)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), state.ServerTimestamp)
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"strconv"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// The key functions return the value that identifies an entity across
+// synchronizations. For entities with an id the key is that id, which
+// models.Deletion documents as the value of Deletion.ID. Budgets have no id
+// and are not deleted through Deletion.
+
+// InstrumentKey returns the key of instrument.
+func InstrumentKey(instrument models.Instrument) string {
+	return strconv.Itoa(instrument.ID)
+}
+
+// CountryKey returns the key of country.
+func CountryKey(country models.Country) string {
+	return strconv.Itoa(country.ID)
+}
+
+// CompanyKey returns the key of company.
+func CompanyKey(company models.Company) string {
+	return strconv.Itoa(company.ID)
+}
+
+// UserKey returns the key of user.
+func UserKey(user models.User) string {
+	return strconv.Itoa(user.ID)
+}
+
+// AccountKey returns the key of account.
+func AccountKey(account models.Account) string {
+	return account.ID
+}
+
+// TagKey returns the key of tag.
+func TagKey(tag models.Tag) string {
+	return tag.ID
+}
+
+// MerchantKey returns the key of merchant.
+func MerchantKey(merchant models.Merchant) string {
+	return merchant.ID
+}
+
+// BudgetKey returns the key of budget. Budgets have no ID and are identified
+// by user, month, and tag; a nil tag denotes the budget of the whole month.
+func BudgetKey(budget models.Budget) string {
+	tag := ""
+	if budget.Tag != nil {
+		tag = *budget.Tag
+	}
+
+	return strconv.Itoa(budget.User) + "/" + budget.Date + "/" + tag
+}
+
+// ReminderKey returns the key of reminder.
+func ReminderKey(reminder models.Reminder) string {
+	return reminder.ID
+}
+
+// ReminderMarkerKey returns the key of marker.
+func ReminderMarkerKey(marker models.ReminderMarker) string {
+	return marker.ID
+}
+
+// TransactionKey returns the key of transaction.
+func TransactionKey(transaction models.Transaction) string {
+	return transaction.ID
+}
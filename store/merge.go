@@ -0,0 +1,76 @@
+// Package store contains helpers shared by local replicas of ZenMoney data.
+//
+// Subpackages persist replicas in specific backends: store/sql mirrors data
+// into a relational database and store/file keeps a compressed snapshot file.
+package store
+
+import (
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Merge applies a synchronization response to a full entity state and returns
+// the new state. Entities from diff replace entities with the same key in
+// state, new entities are appended, entities whose id is listed in
+// diff.Deletion are removed, and ServerTimestamp is taken from diff. Deletions
+// themselves are not kept in the returned state. state is not modified.
+func Merge(state models.Response, diff models.Response) models.Response {
+	removed := make(map[models.EntityType]map[string]bool)
+	for _, deletion := range diff.Deletion {
+		objectType := models.EntityType(deletion.Object)
+		if removed[objectType] == nil {
+			removed[objectType] = make(map[string]bool)
+		}
+		removed[objectType][deletion.ID] = true
+	}
+
+	return models.Response{
+		ServerTimestamp: diff.ServerTimestamp,
+		Instrument:      mergeEntities(state.Instrument, diff.Instrument, removed[models.EntityTypeInstrument], InstrumentKey),
+		Country:         mergeEntities(state.Country, diff.Country, removed[models.EntityTypeCountry], CountryKey),
+		Company:         mergeEntities(state.Company, diff.Company, removed[models.EntityTypeCompany], CompanyKey),
+		User:            mergeEntities(state.User, diff.User, removed[models.EntityTypeUser], UserKey),
+		Account:         mergeEntities(state.Account, diff.Account, removed[models.EntityTypeAccount], AccountKey),
+		Tag:             mergeEntities(state.Tag, diff.Tag, removed[models.EntityTypeTag], TagKey),
+		Merchant:        mergeEntities(state.Merchant, diff.Merchant, removed[models.EntityTypeMerchant], MerchantKey),
+		Budget:          mergeEntities(state.Budget, diff.Budget, nil, BudgetKey),
+		Reminder:        mergeEntities(state.Reminder, diff.Reminder, removed[models.EntityTypeReminder], ReminderKey),
+		ReminderMarker:  mergeEntities(state.ReminderMarker, diff.ReminderMarker, removed[models.EntityTypeReminderMarker], ReminderMarkerKey),
+		Transaction:     mergeEntities(state.Transaction, diff.Transaction, removed[models.EntityTypeTransaction], TransactionKey),
+	}
+}
+
+func mergeEntities[T any](current []T, updates []T, removed map[string]bool, key func(T) string) []T {
+	if len(updates) == 0 && len(removed) == 0 {
+		return current
+	}
+
+	positions := make(map[string]int, len(current)+len(updates))
+	result := make([]T, 0, len(current)+len(updates))
+	for _, entity := range current {
+		positions[key(entity)] = len(result)
+		result = append(result, entity)
+	}
+	for _, entity := range updates {
+		if position, ok := positions[key(entity)]; ok {
+			result[position] = entity
+			continue
+		}
+		positions[key(entity)] = len(result)
+		result = append(result, entity)
+	}
+
+	if len(removed) > 0 {
+		kept := result[:0]
+		for _, entity := range result {
+			if !removed[key(entity)] {
+				kept = append(kept, entity)
+			}
+		}
+		result = kept
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,71 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	tag := "tag-food"
+	state := models.Response{
+		ServerTimestamp: 100,
+		Account:         []models.Account{{ID: "acc-1", Balance: ptr(10.0)}},
+		Budget:          []models.Budget{{User: 1, Date: "2024-01-01", Tag: &tag, Outcome: 50}},
+		Transaction: []models.Transaction{
+			{ID: "tx-1", Outcome: 1},
+			{ID: "tx-2", Outcome: 2},
+			{ID: "tx-3", Outcome: 3},
+		},
+	}
+
+	merged := store.Merge(state, models.Response{
+		ServerTimestamp: 200,
+		Account:         []models.Account{{ID: "acc-1", Balance: ptr(20.0)}},
+		Budget:          []models.Budget{{User: 1, Date: "2024-01-01", Tag: &tag, Outcome: 75}},
+		Transaction: []models.Transaction{
+			{ID: "tx-2", Outcome: 22},
+			{ID: "tx-4", Outcome: 4},
+		},
+		Deletion: []models.Deletion{
+			{ID: "tx-1", Object: string(models.EntityTypeTransaction), Stamp: 200, User: 1},
+			{ID: "acc-missing", Object: string(models.EntityTypeAccount), Stamp: 200, User: 1},
+		},
+	})
+
+	require.Equal(t, int64(200), merged.ServerTimestamp)
+	require.Equal(t, []models.Account{{ID: "acc-1", Balance: ptr(20.0)}}, merged.Account)
+	require.Equal(t, []models.Budget{{User: 1, Date: "2024-01-01", Tag: &tag, Outcome: 75}}, merged.Budget)
+	require.Equal(t, []models.Transaction{
+		{ID: "tx-2", Outcome: 22},
+		{ID: "tx-3", Outcome: 3},
+		{ID: "tx-4", Outcome: 4},
+	}, merged.Transaction)
+	require.Empty(t, merged.Deletion)
+
+	require.Len(t, state.Transaction, 3)
+	require.Equal(t, 2.0, state.Transaction[1].Outcome)
+	require.Equal(t, 10.0, *state.Account[0].Balance)
+}
+
+func TestMergeRemovesLastEntity(t *testing.T) {
+	merged := store.Merge(
+		models.Response{Tag: []models.Tag{{ID: "tag-1"}}},
+		models.Response{Deletion: []models.Deletion{{ID: "tag-1", Object: string(models.EntityTypeTag)}}},
+	)
+
+	require.Nil(t, merged.Tag)
+}
+
+func TestBudgetKey(t *testing.T) {
+	tag := "tag-food"
+
+	require.Equal(t, "1/2024-01-01/tag-food", store.BudgetKey(models.Budget{User: 1, Date: "2024-01-01", Tag: &tag}))
+	require.Equal(t, "1/2024-01-01/", store.BudgetKey(models.Budget{User: 1, Date: "2024-01-01"}))
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,82 @@
+package sql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect adapts generated statements to a database engine.
+type Dialect interface {
+	// Name identifies the dialect in error messages.
+	Name() string
+
+	// Placeholder returns the bind parameter for the n-th argument, starting
+	// at 1.
+	Placeholder(n int) string
+
+	// ColumnType returns the column type for a logical column kind.
+	ColumnType(kind ColumnKind) string
+}
+
+// ColumnKind is the logical type of a column.
+type ColumnKind int
+
+const (
+	KindText ColumnKind = iota
+	KindInteger
+	KindReal
+	KindBoolean
+	// KindJSON holds arrays encoded as JSON text.
+	KindJSON
+)
+
+// SQLite generates statements for SQLite 3.24 or later.
+var SQLite Dialect = sqliteDialect{}
+
+// PostgreSQL generates statements for PostgreSQL 9.5 or later.
+var PostgreSQL Dialect = postgresDialect{}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) Placeholder(int) string { return "?" }
+
+func (sqliteDialect) ColumnType(kind ColumnKind) string {
+	switch kind {
+	case KindInteger, KindBoolean:
+		return "INTEGER"
+	case KindReal:
+		return "REAL"
+	default:
+		return "TEXT"
+	}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+func (postgresDialect) ColumnType(kind ColumnKind) string {
+	switch kind {
+	case KindInteger:
+		return "BIGINT"
+	case KindReal:
+		return "DOUBLE PRECISION"
+	case KindBoolean:
+		return "BOOLEAN"
+	case KindJSON:
+		return "JSONB"
+	default:
+		return "TEXT"
+	}
+}
+
+// quote returns name as a quoted identifier. Both supported dialects use
+// standard double-quoted identifiers, which also protects reserved words such
+// as "user".
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
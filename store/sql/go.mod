@@ -0,0 +1,29 @@
+module github.com/nemirlev/zenmoney-go-sdk/store/sql
+
+go 1.26.0
+
+toolchain go1.27.0
+
+require (
+	github.com/nemirlev/zenmoney-go-sdk/v3 v3.1.0
+	github.com/stretchr/testify v1.12.1
+	modernc.org/sqlite v1.60.1
+)
+
+require (
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mattn/go-isatty v0.0.24 // indirect
+	github.com/ncruces/go-strftime v1.0.0 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+	golang.org/x/sys v0.48.0 // indirect
+	modernc.org/libc v1.77.1 // indirect
+	modernc.org/mathutil v1.7.1 // indirect
+	modernc.org/memory v1.12.1 // indirect
+)
+
+// The replace directive builds against the SDK in this repository during
+// development. Modules that depend on store/sql ignore it and use the SDK
+// version required above.
+replace github.com/nemirlev/zenmoney-go-sdk/v3 => ../..
@@ -0,0 +1,191 @@
+package sql
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+const (
+	deletionTable = "zenmoney_deletion"
+	cursorTable   = "zenmoney_sync_state"
+)
+
+// table describes how one entity type of models.Response is stored.
+type table struct {
+	name       string
+	entityType models.EntityType
+	modelType  reflect.Type
+	columns    []column
+	keys       []string
+}
+
+type column struct {
+	name  string
+	field int
+	kind  ColumnKind
+	// nullable reports whether the Go field accepts nil.
+	nullable bool
+	// emptyAsNull stores nil as an empty string so the column can be part of
+	// a primary key.
+	emptyAsNull bool
+}
+
+// tables lists the entity tables in the order used for schema creation and
+// diff application.
+var tables = []table{
+	newTable("zenmoney_instrument", models.EntityTypeInstrument, models.Instrument{}, "id"),
+	newTable("zenmoney_country", models.EntityTypeCountry, models.Country{}, "id"),
+	newTable("zenmoney_company", models.EntityTypeCompany, models.Company{}, "id"),
+	newTable("zenmoney_user", models.EntityTypeUser, models.User{}, "id"),
+	newTable("zenmoney_account", models.EntityTypeAccount, models.Account{}, "id"),
+	newTable("zenmoney_tag", models.EntityTypeTag, models.Tag{}, "id"),
+	newTable("zenmoney_merchant", models.EntityTypeMerchant, models.Merchant{}, "id"),
+	newTable("zenmoney_budget", models.EntityTypeBudget, models.Budget{}, "user", "date", "tag"),
+	newTable("zenmoney_reminder", models.EntityTypeReminder, models.Reminder{}, "id"),
+	newTable("zenmoney_reminder_marker", models.EntityTypeReminderMarker, models.ReminderMarker{}, "id"),
+	newTable("zenmoney_transaction", models.EntityTypeTransaction, models.Transaction{}, "id"),
+}
+
+func newTable(name string, entityType models.EntityType, model any, keys ...string) table {
+	modelType := reflect.TypeOf(model)
+	result := table{name: name, entityType: entityType, modelType: modelType, keys: keys}
+
+	for i := range modelType.NumField() {
+		field := modelType.Field(i)
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		col := column{name: snakeCase(jsonName), field: i}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			col.nullable = true
+			fieldType = fieldType.Elem()
+		}
+		switch fieldType.Kind() {
+		case reflect.String:
+			col.kind = KindText
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			col.kind = KindInteger
+		case reflect.Float64:
+			col.kind = KindReal
+		case reflect.Bool:
+			col.kind = KindBoolean
+		case reflect.Slice:
+			col.kind = KindJSON
+			col.nullable = true
+		default:
+			panic("store/sql: unsupported field type " + field.Type.String())
+		}
+		if col.nullable && slices.Contains(keys, col.name) {
+			col.nullable = false
+			col.emptyAsNull = true
+		}
+
+		result.columns = append(result.columns, col)
+	}
+
+	return result
+}
+
+func tableFor(entityType models.EntityType) (table, bool) {
+	for _, t := range tables {
+		if t.entityType == entityType {
+			return t, true
+		}
+	}
+
+	return table{}, false
+}
+
+// createStatements returns the DDL for all tables.
+func createStatements(dialect Dialect) []string {
+	statements := make([]string, 0, len(tables)+2)
+	for _, t := range tables {
+		definitions := make([]string, 0, len(t.columns)+1)
+		for _, col := range t.columns {
+			definition := quote(col.name) + " " + dialect.ColumnType(col.kind)
+			if !col.nullable {
+				definition += " NOT NULL"
+			}
+			definitions = append(definitions, definition)
+		}
+		definitions = append(definitions, "PRIMARY KEY ("+quoteList(t.keys)+")")
+		statements = append(statements,
+			"CREATE TABLE IF NOT EXISTS "+quote(t.name)+" (\n\t"+strings.Join(definitions, ",\n\t")+"\n)")
+	}
+
+	statements = append(statements,
+		"CREATE TABLE IF NOT EXISTS "+quote(deletionTable)+" (\n\t"+strings.Join([]string{
+			quote("id") + " " + dialect.ColumnType(KindText) + " NOT NULL",
+			quote("object") + " " + dialect.ColumnType(KindText) + " NOT NULL",
+			quote("stamp") + " " + dialect.ColumnType(KindInteger) + " NOT NULL",
+			quote("user") + " " + dialect.ColumnType(KindInteger) + " NOT NULL",
+			"PRIMARY KEY (" + quoteList([]string{"object", "id", "stamp"}) + ")",
+		}, ",\n\t")+"\n)",
+		"CREATE TABLE IF NOT EXISTS "+quote(cursorTable)+" (\n\t"+strings.Join([]string{
+			quote("id") + " " + dialect.ColumnType(KindInteger) + " NOT NULL PRIMARY KEY",
+			quote("server_timestamp") + " " + dialect.ColumnType(KindInteger) + " NOT NULL",
+		}, ",\n\t")+"\n)",
+	)
+
+	return statements
+}
+
+// upsertStatement returns an INSERT that replaces the row with the same key.
+func upsertStatement(dialect Dialect, t table) string {
+	names := make([]string, len(t.columns))
+	placeholders := make([]string, len(t.columns))
+	var updates []string
+	for i, col := range t.columns {
+		names[i] = col.name
+		placeholders[i] = dialect.Placeholder(i + 1)
+		if !slices.Contains(t.keys, col.name) {
+			updates = append(updates, quote(col.name)+" = excluded."+quote(col.name))
+		}
+	}
+
+	return "INSERT INTO " + quote(t.name) + " (" + quoteList(names) + ") VALUES (" +
+		strings.Join(placeholders, ", ") + ") ON CONFLICT (" + quoteList(t.keys) + ") DO UPDATE SET " +
+		strings.Join(updates, ", ")
+}
+
+func selectStatement(t table) string {
+	names := make([]string, len(t.columns))
+	for i, col := range t.columns {
+		names[i] = col.name
+	}
+
+	return "SELECT " + quoteList(names) + " FROM " + quote(t.name) + " ORDER BY " + quoteList(t.keys)
+}
+
+func quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote(name)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// snakeCase converts JSON field names such as "incomeBankID" to column names
+// such as "income_bank_id".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			previousLower := i > 0 && unicode.IsLower(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])
+			if previousLower || nextLower {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,47 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"id":                      "id",
+		"incomeBankID":            "income_bank_id",
+		"opOutcomeInstrument":     "op_outcome_instrument",
+		"syncID":                  "sync_id",
+		"qrCode":                  "qr_code",
+		"subscriptionRenewalDate": "subscription_renewal_date",
+	}
+
+	for input, want := range tests {
+		require.Equal(t, want, snakeCase(input), input)
+	}
+}
+
+func TestPostgreSQLStatements(t *testing.T) {
+	statements := createStatements(PostgreSQL)
+	require.Len(t, statements, len(tables)+2)
+
+	budget, ok := tableFor("budget")
+	require.True(t, ok)
+	ddl := statements[7]
+	require.Contains(t, ddl, `CREATE TABLE IF NOT EXISTS "zenmoney_budget"`)
+	require.Contains(t, ddl, `"tag" TEXT NOT NULL`)
+	require.Contains(t, ddl, `"is_income_forecast" BOOLEAN NOT NULL`)
+	require.Contains(t, ddl, `PRIMARY KEY ("user", "date", "tag")`)
+
+	upsert := upsertStatement(PostgreSQL, budget)
+	require.True(t, strings.HasPrefix(upsert, `INSERT INTO "zenmoney_budget" ("user", "changed", "date", "tag",`))
+	require.Contains(t, upsert, "VALUES ($1, $2, $3, $4,")
+	require.Contains(t, upsert, `ON CONFLICT ("user", "date", "tag") DO UPDATE SET "changed" = excluded."changed"`)
+	require.NotContains(t, upsert, `"user" = excluded`)
+
+	transaction, ok := tableFor("transaction")
+	require.True(t, ok)
+	require.Contains(t, createStatements(PostgreSQL)[10], `"tag" JSONB`)
+	require.Contains(t, selectStatement(transaction), `"income_bank_id"`)
+}
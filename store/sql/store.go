@@ -0,0 +1,349 @@
+// Package sql mirrors ZenMoney synchronization data into a relational database
+// through database/sql.
+//
+// A Store creates one table per entity type of models.Response, a log of
+// received deletions, and a single-row synchronization cursor. Apply writes a
+// diff in one database transaction, so the stored entities and the cursor
+// always describe the same server state. Statements are generated for the
+// SQLite and PostgreSQL dialects; the caller registers and opens the driver.
+package sql
+
+import (
+	"context"
+	stdsql "database/sql"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Store persists synchronization responses in a database. A Store is safe for
+// concurrent use to the extent that the underlying *sql.DB is.
+type Store struct {
+	db      *stdsql.DB
+	dialect Dialect
+}
+
+// New creates a Store that uses db with dialect. It does not create tables;
+// call Migrate before the first Apply.
+func New(db *stdsql.DB, dialect Dialect) (*Store, error) {
+	if db == nil {
+		return nil, errors.New(errors.ErrInvalidRequest, "database is nil", nil)
+	}
+	if dialect == nil {
+		return nil, errors.New(errors.ErrInvalidRequest, "dialect is nil", nil)
+	}
+
+	return &Store{db: db, dialect: dialect}, nil
+}
+
+// Migrate creates missing tables. It is safe to call on every start.
+func (s *Store) Migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storeError("failed to begin schema migration", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, statement := range createStatements(s.dialect) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return storeError("failed to create schema", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return storeError("failed to commit schema migration", err)
+	}
+
+	return nil
+}
+
+// Apply stores the entities of response, removes entities listed in
+// response.Deletion, appends those deletions to the deletion log, and advances
+// the synchronization cursor to response.ServerTimestamp. As documented on
+// models.Deletion, a deletion names an entity by its id; deletions of unknown
+// objects, of budgets, which have no id, and with an id that does not fit the
+// key column are logged without removing a row. All changes are committed
+// atomically; on error nothing is written.
+func (s *Store) Apply(ctx context.Context, response models.Response) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return storeError("failed to begin transaction", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	source := reflect.ValueOf(response)
+	for _, t := range tables {
+		entities := source.FieldByName(responseField(t))
+		if entities.Len() == 0 {
+			continue
+		}
+
+		statement, err := tx.PrepareContext(ctx, upsertStatement(s.dialect, t))
+		if err != nil {
+			return storeError(fmt.Sprintf("failed to prepare %s upsert", t.entityType), err)
+		}
+		for i := range entities.Len() {
+			args, err := rowValues(t, entities.Index(i))
+			if err != nil {
+				_ = statement.Close()
+				return storeError(fmt.Sprintf("failed to encode %s", t.entityType), err)
+			}
+			if _, err := statement.ExecContext(ctx, args...); err != nil {
+				_ = statement.Close()
+				return storeError(fmt.Sprintf("failed to store %s", t.entityType), err)
+			}
+		}
+		if err := statement.Close(); err != nil {
+			return storeError(fmt.Sprintf("failed to close %s upsert", t.entityType), err)
+		}
+	}
+
+	if err := s.applyDeletions(ctx, tx, response.Deletion); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO "+quote(cursorTable)+" ("+quoteList([]string{"id", "server_timestamp"})+") VALUES ("+
+			s.dialect.Placeholder(1)+", "+s.dialect.Placeholder(2)+") ON CONFLICT ("+quote("id")+
+			") DO UPDATE SET "+quote("server_timestamp")+" = excluded."+quote("server_timestamp"),
+		1, response.ServerTimestamp,
+	); err != nil {
+		return storeError("failed to store synchronization cursor", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return storeError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+func (s *Store) applyDeletions(ctx context.Context, tx *stdsql.Tx, deletions []models.Deletion) error {
+	for _, deletion := range deletions {
+		if t, key, ok := deletedRow(deletion); ok {
+			if _, err := tx.ExecContext(ctx,
+				"DELETE FROM "+quote(t.name)+" WHERE "+quote(t.keys[0])+" = "+s.dialect.Placeholder(1),
+				key,
+			); err != nil {
+				return storeError(fmt.Sprintf("failed to delete %s %s", deletion.Object, deletion.ID), err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO "+quote(deletionTable)+" ("+quoteList([]string{"id", "object", "stamp", "user"})+") VALUES ("+
+				s.dialect.Placeholder(1)+", "+s.dialect.Placeholder(2)+", "+
+				s.dialect.Placeholder(3)+", "+s.dialect.Placeholder(4)+") ON CONFLICT ("+
+				quoteList([]string{"object", "id", "stamp"})+") DO NOTHING",
+			deletion.ID, deletion.Object, deletion.Stamp, deletion.User,
+		); err != nil {
+			return storeError("failed to log deletion", err)
+		}
+	}
+
+	return nil
+}
+
+// deletedRow returns the table and the key value of the row that deletion
+// removes. It reports false when deletion does not name a row by id.
+func deletedRow(deletion models.Deletion) (table, any, bool) {
+	t, ok := tableFor(models.EntityType(deletion.Object))
+	if !ok || len(t.keys) != 1 {
+		return table{}, nil, false
+	}
+	key := t.columns[slices.IndexFunc(t.columns, func(col column) bool { return col.name == t.keys[0] })]
+	if key.kind != KindInteger {
+		return t, deletion.ID, true
+	}
+	id, err := strconv.ParseInt(deletion.ID, 10, 64)
+	if err != nil {
+		return table{}, nil, false
+	}
+
+	return t, id, true
+}
+
+// ServerTimestamp returns the cursor stored by the last Apply, or zero when
+// nothing has been applied yet. Pass the result to Client.SyncSince as a Unix
+// time.
+func (s *Store) ServerTimestamp(ctx context.Context) (int64, error) {
+	var timestamp int64
+	err := s.db.QueryRowContext(ctx,
+		"SELECT "+quote("server_timestamp")+" FROM "+quote(cursorTable)+" WHERE "+quote("id")+" = "+s.dialect.Placeholder(1),
+		1,
+	).Scan(&timestamp)
+	if stdErrors.Is(err, stdsql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, storeError("failed to read synchronization cursor", err)
+	}
+
+	return timestamp, nil
+}
+
+// Load reads every stored entity and the cursor into a response. Deletion
+// contains the deletion log in the order it was received.
+func (s *Store) Load(ctx context.Context) (models.Response, error) {
+	var response models.Response
+	timestamp, err := s.ServerTimestamp(ctx)
+	if err != nil {
+		return models.Response{}, err
+	}
+	response.ServerTimestamp = timestamp
+
+	target := reflect.ValueOf(&response).Elem()
+	for _, t := range tables {
+		entities, err := s.loadTable(ctx, t)
+		if err != nil {
+			return models.Response{}, err
+		}
+		target.FieldByName(responseField(t)).Set(entities)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+quoteList([]string{"id", "object", "stamp", "user"})+" FROM "+quote(deletionTable)+
+			" ORDER BY "+quoteList([]string{"stamp", "object", "id"}),
+	)
+	if err != nil {
+		return models.Response{}, storeError("failed to query deletions", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var deletion models.Deletion
+		if err := rows.Scan(&deletion.ID, &deletion.Object, &deletion.Stamp, &deletion.User); err != nil {
+			return models.Response{}, storeError("failed to read deletion", err)
+		}
+		response.Deletion = append(response.Deletion, deletion)
+	}
+	if err := rows.Err(); err != nil {
+		return models.Response{}, storeError("failed to read deletions", err)
+	}
+
+	return response, nil
+}
+
+func (s *Store) loadTable(ctx context.Context, t table) (reflect.Value, error) {
+	entities := reflect.MakeSlice(reflect.SliceOf(t.modelType), 0, 0)
+	rows, err := s.db.QueryContext(ctx, selectStatement(t))
+	if err != nil {
+		return entities, storeError(fmt.Sprintf("failed to query %s", t.entityType), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity, err := scanRow(t, rows)
+		if err != nil {
+			return entities, storeError(fmt.Sprintf("failed to read %s", t.entityType), err)
+		}
+		entities = reflect.Append(entities, entity)
+	}
+	if err := rows.Err(); err != nil {
+		return entities, storeError(fmt.Sprintf("failed to read %s", t.entityType), err)
+	}
+	if entities.Len() == 0 {
+		return reflect.Zero(entities.Type()), nil
+	}
+
+	return entities, nil
+}
+
+// rowValues converts entity into statement arguments in column order.
+func rowValues(t table, entity reflect.Value) ([]any, error) {
+	args := make([]any, len(t.columns))
+	for i, col := range t.columns {
+		value := entity.Field(col.field)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				if col.emptyAsNull {
+					args[i] = ""
+				}
+				continue
+			}
+			value = value.Elem()
+		}
+
+		switch col.kind {
+		case KindJSON:
+			if value.IsNil() {
+				continue
+			}
+			encoded, err := json.Marshal(value.Interface())
+			if err != nil {
+				return nil, err
+			}
+			args[i] = string(encoded)
+		case KindInteger:
+			args[i] = value.Int()
+		default:
+			args[i] = value.Interface()
+		}
+	}
+
+	return args, nil
+}
+
+func scanRow(t table, rows *stdsql.Rows) (reflect.Value, error) {
+	destinations := make([]any, len(t.columns))
+	for i, col := range t.columns {
+		switch col.kind {
+		case KindInteger:
+			destinations[i] = &stdsql.NullInt64{}
+		case KindReal:
+			destinations[i] = &stdsql.NullFloat64{}
+		case KindBoolean:
+			destinations[i] = &stdsql.NullBool{}
+		default:
+			destinations[i] = &stdsql.NullString{}
+		}
+	}
+	if err := rows.Scan(destinations...); err != nil {
+		return reflect.Value{}, err
+	}
+
+	entity := reflect.New(t.modelType).Elem()
+	for i, col := range t.columns {
+		var value any
+		valid := false
+		switch destination := destinations[i].(type) {
+		case *stdsql.NullInt64:
+			value, valid = destination.Int64, destination.Valid
+		case *stdsql.NullFloat64:
+			value, valid = destination.Float64, destination.Valid
+		case *stdsql.NullBool:
+			value, valid = destination.Bool, destination.Valid
+		case *stdsql.NullString:
+			value, valid = destination.String, destination.Valid && !(col.emptyAsNull && destination.String == "")
+		}
+		if !valid {
+			continue
+		}
+
+		field := entity.Field(col.field)
+		if col.kind == KindJSON {
+			if err := json.Unmarshal([]byte(value.(string)), field.Addr().Interface()); err != nil {
+				return reflect.Value{}, err
+			}
+			continue
+		}
+		if field.Kind() == reflect.Pointer {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		field.Set(reflect.ValueOf(value).Convert(field.Type()))
+	}
+
+	return entity, nil
+}
+
+func responseField(t table) string {
+	return t.modelType.Name()
+}
+
+func storeError(message string, err error) error {
+	return errors.New(errors.ErrStorage, message, err)
+}
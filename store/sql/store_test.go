@@ -0,0 +1,153 @@
+package sql_test
+
+import (
+	"context"
+	stdsql "database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/store/sql"
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func openStore(t *testing.T) (*sql.Store, *stdsql.DB) {
+	db, err := stdsql.Open("sqlite", filepath.Join(t.TempDir(), "zenmoney.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	store, err := sql.New(db, sql.SQLite)
+	require.NoError(t, err)
+	require.NoError(t, store.Migrate(context.Background()))
+	require.NoError(t, store.Migrate(context.Background()))
+
+	return store, db
+}
+
+func TestStoreRoundTripsFixture(t *testing.T) {
+	payload, err := os.ReadFile("../../example.json")
+	require.NoError(t, err)
+	var response models.Response
+	require.NoError(t, json.Unmarshal(payload, &response))
+	store, _ := openStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Apply(ctx, response))
+
+	loaded, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, response, loaded)
+}
+
+func TestStoreAppliesDiffs(t *testing.T) {
+	store, _ := openStore(t)
+	ctx := context.Background()
+	tag := "tag-food"
+	comment := "first"
+
+	timestamp, err := store.ServerTimestamp(ctx)
+	require.NoError(t, err)
+	require.Zero(t, timestamp)
+
+	require.NoError(t, store.Apply(ctx, models.Response{
+		ServerTimestamp: 100,
+		Transaction: []models.Transaction{
+			{ID: "tx-1", Date: "2024-01-01", Outcome: 10, Tag: []string{"tag-food"}, Comment: &comment},
+			{ID: "tx-2", Date: "2024-01-02", Outcome: 20},
+		},
+		Budget: []models.Budget{
+			{User: 1, Date: "2024-01-01", Outcome: 100},
+			{User: 1, Date: "2024-01-01", Tag: &tag, Outcome: 50},
+		},
+	}))
+
+	require.NoError(t, store.Apply(ctx, models.Response{
+		ServerTimestamp: 200,
+		Transaction:     []models.Transaction{{ID: "tx-1", Date: "2024-01-01", Outcome: 15}},
+		Budget:          []models.Budget{{User: 1, Date: "2024-01-01", Outcome: 150}},
+		Deletion:        []models.Deletion{{ID: "tx-2", Object: "transaction", Stamp: 150, User: 1}},
+	}))
+
+	loaded, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), loaded.ServerTimestamp)
+	require.Equal(t, []models.Transaction{{ID: "tx-1", Date: "2024-01-01", Outcome: 15}}, loaded.Transaction)
+	require.Len(t, loaded.Budget, 2)
+	require.Nil(t, loaded.Budget[0].Tag)
+	require.Equal(t, 150.0, loaded.Budget[0].Outcome)
+	require.Equal(t, "tag-food", *loaded.Budget[1].Tag)
+	require.Equal(t, []models.Deletion{{ID: "tx-2", Object: "transaction", Stamp: 150, User: 1}}, loaded.Deletion)
+}
+
+func TestStoreLogsDeletionsWithoutRow(t *testing.T) {
+	store, _ := openStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Apply(ctx, models.Response{
+		ServerTimestamp: 100,
+		Instrument:      []models.Instrument{{ID: 1, ShortTitle: "USD"}, {ID: 2, ShortTitle: "RUB"}},
+		Budget:          []models.Budget{{User: 1, Date: "2024-01-01", Outcome: 100}},
+	}))
+	deletions := []models.Deletion{
+		{ID: "1/2024-01-01/", Object: "budget", Stamp: 150, User: 1},
+		{ID: "usd", Object: "instrument", Stamp: 150, User: 1},
+		{ID: "2", Object: "instrument", Stamp: 150, User: 1},
+		{ID: "x", Object: "unknown", Stamp: 150, User: 1},
+	}
+
+	require.NoError(t, store.Apply(ctx, models.Response{ServerTimestamp: 200, Deletion: deletions}))
+
+	loaded, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(200), loaded.ServerTimestamp)
+	require.Equal(t, []models.Instrument{{ID: 1, ShortTitle: "USD"}}, loaded.Instrument)
+	require.Len(t, loaded.Budget, 1)
+	require.ElementsMatch(t, deletions, loaded.Deletion)
+}
+
+func TestStoreApplyIsAtomic(t *testing.T) {
+	store, db := openStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Apply(ctx, models.Response{ServerTimestamp: 100}))
+	_, err := db.ExecContext(ctx, `DROP TABLE "zenmoney_deletion"`)
+	require.NoError(t, err)
+
+	err = store.Apply(ctx, models.Response{
+		ServerTimestamp: 200,
+		Account:         []models.Account{{ID: "account-1", Title: "Cash"}},
+		Deletion:        []models.Deletion{{ID: "x", Object: "tag", Stamp: 1}},
+	})
+
+	var sdkErr *sdkerrors.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, sdkerrors.ErrStorage, sdkErr.Code)
+	timestamp, err := store.ServerTimestamp(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), timestamp)
+	var accounts int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "zenmoney_account"`).Scan(&accounts))
+	require.Zero(t, accounts)
+}
+
+func TestNewValidatesArguments(t *testing.T) {
+	_, err := sql.New(nil, sql.SQLite)
+	require.Error(t, err)
+
+	db, err := stdsql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = sql.New(db, nil)
+	require.Error(t, err)
+}
+
+func TestDialects(t *testing.T) {
+	require.Equal(t, "?", sql.SQLite.Placeholder(3))
+	require.Equal(t, "$3", sql.PostgreSQL.Placeholder(3))
+	require.Equal(t, "INTEGER", sql.SQLite.ColumnType(sql.KindBoolean))
+	require.Equal(t, "BOOLEAN", sql.PostgreSQL.ColumnType(sql.KindBoolean))
+	require.Equal(t, "JSONB", sql.PostgreSQL.ColumnType(sql.KindJSON))
+	require.Equal(t, "DOUBLE PRECISION", sql.PostgreSQL.ColumnType(sql.KindReal))
+}
@@ -0,0 +1,252 @@
+// Package suggestcache caches ZenMoney suggestion results by payee.
+//
+// A Cache wraps anything that implements Suggest, such as *api.Client, and
+// remembers the suggestion for each normalized payee. Storage is pluggable;
+// NewLRU provides a bounded in-memory store with optional expiry. Cached
+// suggestions are dropped when a synchronization response shows that the user
+// categorized a transaction for the same payee differently.
+package suggestcache
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/internal/finance"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Suggester requests a remote suggestion. *api.Client implements Suggester.
+type Suggester interface {
+	Suggest(ctx context.Context, transaction models.Transaction) (models.Transaction, error)
+}
+
+// Storage persists cached suggestions. Only the suggested Payee, Tag, and
+// Merchant of a transaction are stored. Implementations must be safe for
+// concurrent use. Get reports false when key is not cached or has expired.
+type Storage interface {
+	Get(ctx context.Context, key string) (models.Transaction, bool, error)
+	Set(ctx context.Context, key string, suggestion models.Transaction) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Stats contains cache counters since the Cache was created.
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Invalidations uint64
+	// StorageErrors counts failed Storage calls. A failed lookup is treated as
+	// a miss, and a failed write leaves the suggestion uncached.
+	StorageErrors uint64
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithStorage replaces the default in-memory storage, an LRU of 1024 entries
+// without expiry.
+func WithStorage(storage Storage) Option {
+	return func(c *Cache) {
+		c.storage = storage
+	}
+}
+
+// WithAmountSign adds the transaction direction to cache keys, so income and
+// expenses with the same payee are cached separately.
+func WithAmountSign(enabled bool) Option {
+	return func(c *Cache) {
+		c.amountSign = enabled
+	}
+}
+
+// Cache serves suggestions from storage and falls back to a Suggester on a
+// miss. A Cache is safe for concurrent use.
+type Cache struct {
+	suggester  Suggester
+	storage    Storage
+	amountSign bool
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	invalidations atomic.Uint64
+	storageErrors atomic.Uint64
+}
+
+// New creates a Cache in front of suggester.
+func New(suggester Suggester, opts ...Option) *Cache {
+	c := &Cache{suggester: suggester}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.storage == nil {
+		c.storage = NewLRU(1024, 0)
+	}
+
+	return c
+}
+
+// Suggest returns the cached suggestion for the payee of transaction or asks
+// the underlying Suggester and caches its answer. On a hit, the cached Payee,
+// Tag, and Merchant are applied to transaction and its other fields are
+// returned unchanged. Transactions without a payee bypass the cache.
+func (c *Cache) Suggest(ctx context.Context, transaction models.Transaction) (models.Transaction, error) {
+	key := c.Key(transaction)
+	if key == "" {
+		return c.suggester.Suggest(ctx, transaction)
+	}
+
+	cached, ok, err := c.storage.Get(ctx, key)
+	if err != nil {
+		c.storageErrors.Add(1)
+	}
+	if ok && err == nil {
+		c.hits.Add(1)
+		return applySuggestion(transaction, cached), nil
+	}
+	c.misses.Add(1)
+
+	suggestion, err := c.suggester.Suggest(ctx, transaction)
+	if err != nil {
+		return models.Transaction{}, err
+	}
+	if err := c.storage.Set(ctx, key, suggestedFields(suggestion)); err != nil {
+		c.storageErrors.Add(1)
+	}
+
+	return suggestion, nil
+}
+
+// Key returns the cache key of transaction: its normalized original payee, or
+// payee when the original is empty, optionally followed by the direction. An
+// empty key means the transaction is not cacheable.
+func (c *Cache) Key(transaction models.Transaction) string {
+	payee := transaction.OriginalPayee
+	if payee == "" {
+		payee = transaction.Payee
+	}
+	key := finance.NormalizePayee(payee)
+	if key == "" || !c.amountSign {
+		return key
+	}
+
+	switch {
+	case transaction.Outcome > 0:
+		return key + "|-"
+	case transaction.Income > 0:
+		return key + "|+"
+	default:
+		return key
+	}
+}
+
+// Invalidate removes the cached suggestion for the payee of transaction.
+func (c *Cache) Invalidate(ctx context.Context, transaction models.Transaction) error {
+	key := c.Key(transaction)
+	if key == "" {
+		return nil
+	}
+	if err := c.storage.Delete(ctx, key); err != nil {
+		c.storageErrors.Add(1)
+		return err
+	}
+	c.invalidations.Add(1)
+
+	return nil
+}
+
+// InvalidateFromSync compares the transactions in a synchronization response
+// with cached suggestions and removes entries whose tags or merchant no longer
+// agree with how the user categorized that payee. It returns the number of
+// removed entries and the first storage error, if any.
+func (c *Cache) InvalidateFromSync(ctx context.Context, response models.Response) (int, error) {
+	removed := 0
+	var firstErr error
+	for _, transaction := range response.Transaction {
+		if transaction.Deleted {
+			continue
+		}
+		key := c.Key(transaction)
+		if key == "" {
+			continue
+		}
+
+		cached, ok, err := c.storage.Get(ctx, key)
+		if err != nil {
+			c.storageErrors.Add(1)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if !ok || sameCategorization(cached, transaction) {
+			continue
+		}
+		if err := c.storage.Delete(ctx, key); err != nil {
+			c.storageErrors.Add(1)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		c.invalidations.Add(1)
+		removed++
+	}
+
+	return removed, firstErr
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+		StorageErrors: c.storageErrors.Load(),
+	}
+}
+
+func sameCategorization(suggestion models.Transaction, transaction models.Transaction) bool {
+	if len(transaction.Tag) > 0 && !slices.Equal(suggestion.Tag, transaction.Tag) {
+		return false
+	}
+	if transaction.Merchant != nil && (suggestion.Merchant == nil || *suggestion.Merchant != *transaction.Merchant) {
+		return false
+	}
+
+	return true
+}
+
+// suggestedFields returns the part of suggestion that is cached: the fields
+// ZenMoney fills in for a payee. The result shares no memory with suggestion.
+func suggestedFields(suggestion models.Transaction) models.Transaction {
+	return models.Transaction{
+		Payee:    suggestion.Payee,
+		Tag:      slices.Clone(suggestion.Tag),
+		Merchant: clonePointer(suggestion.Merchant),
+	}
+}
+
+// applySuggestion copies the non-empty suggested fields of cached into
+// transaction without sharing memory with the cache.
+func applySuggestion(transaction models.Transaction, cached models.Transaction) models.Transaction {
+	if cached.Payee != "" {
+		transaction.Payee = cached.Payee
+	}
+	if len(cached.Tag) > 0 {
+		transaction.Tag = slices.Clone(cached.Tag)
+	}
+	if cached.Merchant != nil {
+		transaction.Merchant = clonePointer(cached.Merchant)
+	}
+
+	return transaction
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+
+	return &clone
+}
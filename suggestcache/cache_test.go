@@ -0,0 +1,197 @@
+package suggestcache_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/suggestcache"
+	"github.com/stretchr/testify/require"
+)
+
+type countingSuggester struct {
+	calls int
+	err   error
+}
+
+func (s *countingSuggester) Suggest(_ context.Context, transaction models.Transaction) (models.Transaction, error) {
+	s.calls++
+	if s.err != nil {
+		return models.Transaction{}, s.err
+	}
+	merchant := "merchant-" + transaction.Payee
+	transaction.Tag = []string{"tag-food"}
+	transaction.Merchant = &merchant
+
+	return transaction, nil
+}
+
+type failingStorage struct{}
+
+func (failingStorage) Get(context.Context, string) (models.Transaction, bool, error) {
+	return models.Transaction{}, false, stdErrors.New("storage unavailable")
+}
+
+func (failingStorage) Set(context.Context, string, models.Transaction) error {
+	return stdErrors.New("storage unavailable")
+}
+
+func (failingStorage) Delete(context.Context, string) error {
+	return stdErrors.New("storage unavailable")
+}
+
+func TestCacheSuggest(t *testing.T) {
+	suggester := &countingSuggester{}
+	cache := suggestcache.New(suggester)
+	ctx := context.Background()
+
+	first, err := cache.Suggest(ctx, models.Transaction{Payee: "Coffee-House"})
+	require.NoError(t, err)
+	first.Tag[0] = "mutated"
+
+	second, err := cache.Suggest(ctx, models.Transaction{Payee: "  coffee house "})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, suggester.calls)
+	require.Equal(t, []string{"tag-food"}, second.Tag)
+	require.Equal(t, suggestcache.Stats{Hits: 1, Misses: 1}, cache.Stats())
+}
+
+func TestCacheKeepsCallerFieldsOnHit(t *testing.T) {
+	suggester := &countingSuggester{}
+	cache := suggestcache.New(suggester)
+	ctx := context.Background()
+	card := "card"
+
+	_, err := cache.Suggest(ctx, models.Transaction{ID: "tx-1", Date: "2024-05-01", Payee: "Coffee House", Outcome: 150})
+	require.NoError(t, err)
+
+	suggestion, err := cache.Suggest(ctx, models.Transaction{
+		ID:             "tx-2",
+		Date:           "2024-05-02",
+		Payee:          "Coffee House",
+		Outcome:        320,
+		IncomeAccount:  card,
+		OutcomeAccount: &card,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, suggester.calls)
+	require.Equal(t, "tx-2", suggestion.ID)
+	require.Equal(t, "2024-05-02", suggestion.Date)
+	require.Equal(t, 320.0, suggestion.Outcome)
+	require.Zero(t, suggestion.Income)
+	require.Equal(t, card, suggestion.IncomeAccount)
+	require.Equal(t, &card, suggestion.OutcomeAccount)
+	require.Equal(t, []string{"tag-food"}, suggestion.Tag)
+	require.Equal(t, "merchant-Coffee House", *suggestion.Merchant)
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	suggester := &countingSuggester{}
+	cache := suggestcache.New(suggester)
+	ctx := context.Background()
+
+	first, err := cache.Suggest(ctx, models.Transaction{Payee: "Coffee House"})
+	require.NoError(t, err)
+	*first.Merchant = "mutated"
+
+	second, err := cache.Suggest(ctx, models.Transaction{Payee: "Coffee House"})
+	require.NoError(t, err)
+	*second.Merchant = "mutated again"
+	second.Tag[0] = "mutated"
+
+	third, err := cache.Suggest(ctx, models.Transaction{Payee: "Coffee House"})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, suggester.calls)
+	require.Equal(t, "merchant-Coffee House", *third.Merchant)
+	require.Equal(t, []string{"tag-food"}, third.Tag)
+}
+
+func TestCacheBypassesEmptyPayee(t *testing.T) {
+	suggester := &countingSuggester{}
+	cache := suggestcache.New(suggester)
+
+	_, err := cache.Suggest(context.Background(), models.Transaction{})
+	require.NoError(t, err)
+	_, err = cache.Suggest(context.Background(), models.Transaction{})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, suggester.calls)
+	require.Equal(t, suggestcache.Stats{}, cache.Stats())
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	cause := stdErrors.New("suggest failed")
+	suggester := &countingSuggester{err: cause}
+	cache := suggestcache.New(suggester)
+
+	_, err := cache.Suggest(context.Background(), models.Transaction{Payee: "Shop"})
+	require.ErrorIs(t, err, cause)
+
+	suggester.err = nil
+	_, err = cache.Suggest(context.Background(), models.Transaction{Payee: "Shop"})
+	require.NoError(t, err)
+	require.Equal(t, 2, suggester.calls)
+}
+
+func TestCacheKeyWithAmountSign(t *testing.T) {
+	cache := suggestcache.New(&countingSuggester{}, suggestcache.WithAmountSign(true))
+
+	require.Equal(t, "ivan petrov|-", cache.Key(models.Transaction{OriginalPayee: "Ivan Petrov", Payee: "Ivan", Outcome: 10}))
+	require.Equal(t, "ivan|+", cache.Key(models.Transaction{Payee: "Ivan", Income: 10}))
+	require.Equal(t, "ivan", cache.Key(models.Transaction{Payee: "Ivan"}))
+}
+
+func TestCacheInvalidateFromSync(t *testing.T) {
+	suggester := &countingSuggester{}
+	cache := suggestcache.New(suggester)
+	ctx := context.Background()
+	for _, payee := range []string{"Bakery", "Cinema", "Taxi"} {
+		_, err := cache.Suggest(ctx, models.Transaction{Payee: payee})
+		require.NoError(t, err)
+	}
+	unchanged := "merchant-Cinema"
+
+	removed, err := cache.InvalidateFromSync(ctx, models.Response{
+		Transaction: []models.Transaction{
+			{Payee: "Bakery", Tag: []string{"tag-sweets"}},
+			{Payee: "Cinema", Tag: []string{"tag-food"}, Merchant: &unchanged},
+			{Payee: "Taxi", Tag: []string{"tag-transport"}, Deleted: true},
+			{Payee: "Unknown", Tag: []string{"tag-other"}},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	require.Equal(t, uint64(1), cache.Stats().Invalidations)
+
+	_, err = cache.Suggest(ctx, models.Transaction{Payee: "Bakery"})
+	require.NoError(t, err)
+	_, err = cache.Suggest(ctx, models.Transaction{Payee: "Cinema"})
+	require.NoError(t, err)
+	require.Equal(t, 4, suggester.calls)
+
+	require.NoError(t, cache.Invalidate(ctx, models.Transaction{Payee: "Cinema"}))
+	_, err = cache.Suggest(ctx, models.Transaction{Payee: "Cinema"})
+	require.NoError(t, err)
+	require.Equal(t, 5, suggester.calls)
+}
+
+func TestCacheDegradesOnStorageErrors(t *testing.T) {
+	suggester := &countingSuggester{}
+	cache := suggestcache.New(suggester, suggestcache.WithStorage(failingStorage{}))
+
+	suggestion, err := cache.Suggest(context.Background(), models.Transaction{Payee: "Shop"})
+
+	require.NoError(t, err)
+	require.Equal(t, "Shop", suggestion.Payee)
+	require.Equal(t, suggestcache.Stats{Misses: 1, StorageErrors: 2}, cache.Stats())
+
+	_, err = cache.InvalidateFromSync(context.Background(), models.Response{
+		Transaction: []models.Transaction{{Payee: "Shop"}},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package suggestcache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// LRU is an in-memory Storage that evicts the least recently used entry when
+// full and optionally expires entries after a fixed time to live.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	clock    models.Clock
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type lruEntry struct {
+	key        string
+	suggestion models.Transaction
+	expiresAt  time.Time
+}
+
+// LRUOption configures an LRU.
+type LRUOption func(*LRU)
+
+// WithClock sets the clock that expires entries. The default is
+// models.SystemClock, and a nil clock restores it.
+func WithClock(clock models.Clock) LRUOption {
+	return func(l *LRU) {
+		l.clock = clock
+	}
+}
+
+// NewLRU creates an LRU holding at most capacity entries. A capacity below 1
+// is treated as 1. A zero ttl keeps entries until they are evicted.
+func NewLRU(capacity int, ttl time.Duration, opts ...LRUOption) *LRU {
+	l := &LRU{
+		capacity: max(capacity, 1),
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.clock == nil {
+		l.clock = models.SystemClock{}
+	}
+
+	return l
+}
+
+// Get returns the entry stored under key and marks it as recently used.
+func (l *LRU) Get(_ context.Context, key string) (models.Transaction, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return models.Transaction{}, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !l.clock.Now().Before(entry.expiresAt) {
+		l.remove(element)
+		return models.Transaction{}, false, nil
+	}
+	l.order.MoveToFront(element)
+
+	return entry.suggestion, true, nil
+}
+
+// Set stores suggestion under key, evicting the least recently used entry
+// when the LRU is full.
+func (l *LRU) Set(_ context.Context, key string, suggestion models.Transaction) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var expiresAt time.Time
+	if l.ttl > 0 {
+		expiresAt = l.clock.Now().Add(l.ttl)
+	}
+	if element, ok := l.entries[key]; ok {
+		element.Value = &lruEntry{key: key, suggestion: suggestion, expiresAt: expiresAt}
+		l.order.MoveToFront(element)
+		return nil
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, suggestion: suggestion, expiresAt: expiresAt})
+	if l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+
+	return nil
+}
+
+// Delete removes key from the LRU.
+func (l *LRU) Delete(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+
+	return nil
+}
+
+// Len returns the number of stored entries, including expired entries that
+// have not been accessed since they expired.
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *LRU) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,67 @@
+package suggestcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/stretchr/testify/require"
+)
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("evicts least recently used entry", func(t *testing.T) {
+		lru := NewLRU(2, 0)
+		require.NoError(t, lru.Set(ctx, "a", models.Transaction{Payee: "A"}))
+		require.NoError(t, lru.Set(ctx, "b", models.Transaction{Payee: "B"}))
+		_, ok, err := lru.Get(ctx, "a")
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.NoError(t, lru.Set(ctx, "c", models.Transaction{Payee: "C"}))
+
+		_, ok, _ = lru.Get(ctx, "b")
+		require.False(t, ok)
+		got, ok, _ := lru.Get(ctx, "a")
+		require.True(t, ok)
+		require.Equal(t, "A", got.Payee)
+		require.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("expires entries after ttl", func(t *testing.T) {
+		clock := &manualClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		lru := NewLRU(10, time.Minute, WithClock(clock))
+		require.NoError(t, lru.Set(ctx, "a", models.Transaction{Payee: "A"}))
+
+		clock.now = clock.now.Add(59 * time.Second)
+		_, ok, _ := lru.Get(ctx, "a")
+		require.True(t, ok)
+
+		clock.now = clock.now.Add(time.Second)
+		_, ok, _ = lru.Get(ctx, "a")
+		require.False(t, ok)
+		require.Zero(t, lru.Len())
+	})
+
+	t.Run("replaces and deletes entries", func(t *testing.T) {
+		lru := NewLRU(0, 0)
+		require.NoError(t, lru.Set(ctx, "a", models.Transaction{Payee: "A"}))
+		require.NoError(t, lru.Set(ctx, "a", models.Transaction{Payee: "A2"}))
+		got, _, _ := lru.Get(ctx, "a")
+		require.Equal(t, "A2", got.Payee)
+
+		require.NoError(t, lru.Delete(ctx, "a"))
+		require.NoError(t, lru.Delete(ctx, "missing"))
+		require.Zero(t, lru.Len())
+	})
+}
@@ -0,0 +1,105 @@
+package watch
+
+import (
+	"context"
+	stdErrors "errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+)
+
+// Cursor stores the server timestamp of the last successful poll. Load returns
+// zero when no timestamp has been saved.
+type Cursor interface {
+	Load(ctx context.Context) (int64, error)
+	Save(ctx context.Context, serverTimestamp int64) error
+}
+
+// MemoryCursor keeps the timestamp in memory. The zero value is ready to use.
+type MemoryCursor struct {
+	mu        sync.Mutex
+	timestamp int64
+}
+
+// Load returns the saved timestamp.
+func (c *MemoryCursor) Load(context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.timestamp, nil
+}
+
+// Save replaces the saved timestamp.
+func (c *MemoryCursor) Save(_ context.Context, serverTimestamp int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timestamp = serverTimestamp
+	return nil
+}
+
+// FileCursor keeps the timestamp as decimal text in a file. Save replaces the
+// file atomically, so a crash leaves either the old or the new timestamp.
+type FileCursor struct {
+	path string
+}
+
+// NewFileCursor returns a cursor stored at path. The file is created by the
+// first Save.
+func NewFileCursor(path string) *FileCursor {
+	return &FileCursor{path: path}
+}
+
+// Load reads the saved timestamp. A missing file yields zero.
+func (c *FileCursor) Load(context.Context) (int64, error) {
+	content, err := os.ReadFile(c.path)
+	if stdErrors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.New(errors.ErrStorage, "failed to read watcher cursor", err)
+	}
+
+	timestamp, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, errors.New(errors.ErrStorage, "watcher cursor is not a timestamp", err)
+	}
+
+	return timestamp, nil
+}
+
+// Save writes serverTimestamp to the file.
+func (c *FileCursor) Save(_ context.Context, serverTimestamp int64) error {
+	temporary, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return errors.New(errors.ErrStorage, "failed to create temporary watcher cursor", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = temporary.Close()
+			_ = os.Remove(temporary.Name())
+		}
+	}()
+
+	if _, err := temporary.WriteString(strconv.FormatInt(serverTimestamp, 10) + "\n"); err != nil {
+		return errors.New(errors.ErrStorage, "failed to write watcher cursor", err)
+	}
+	if err := temporary.Sync(); err != nil {
+		return errors.New(errors.ErrStorage, "failed to flush watcher cursor", err)
+	}
+	if err := temporary.Close(); err != nil {
+		return errors.New(errors.ErrStorage, "failed to close watcher cursor", err)
+	}
+	if err := os.Rename(temporary.Name(), c.path); err != nil {
+		return errors.New(errors.ErrStorage, "failed to replace watcher cursor", err)
+	}
+	committed = true
+
+	return nil
+}
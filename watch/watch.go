@@ -0,0 +1,315 @@
+// Package watch polls ZenMoney for changes and reports them as typed events.
+//
+// A Watcher calls SyncSince on a fixed interval, compares the response with
+// what it has already seen, and delivers TransactionAdded,
+// TransactionUpdated, AccountBalanceChanged, and EntityDeleted events to a
+// callback or a channel. The server timestamp of every successful poll is
+// saved to a Cursor, so a restarted watcher continues where it stopped.
+// Failed polls are retried with exponential backoff until the context is
+// canceled.
+package watch
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+)
+
+// Syncer performs incremental synchronization. *api.Client implements Syncer.
+type Syncer interface {
+	SyncSince(ctx context.Context, lastSync time.Time) (models.Response, error)
+}
+
+// Event is one of TransactionAdded, TransactionUpdated,
+// AccountBalanceChanged, or EntityDeleted.
+type Event interface {
+	event()
+}
+
+// TransactionAdded reports a transaction the watcher has not seen before.
+type TransactionAdded struct {
+	Transaction models.Transaction
+}
+
+// TransactionUpdated reports a change to a known transaction.
+type TransactionUpdated struct {
+	Transaction models.Transaction
+}
+
+// AccountBalanceChanged reports a new account balance. Previous is nil when
+// the watcher did not know the balance, which happens for accounts changed
+// while the watcher was stopped.
+type AccountBalanceChanged struct {
+	Account  models.Account
+	Previous *float64
+}
+
+// EntityDeleted reports a deletion. Transactions marked as deleted are
+// reported as deletions of the transaction object.
+type EntityDeleted struct {
+	Deletion models.Deletion
+}
+
+func (TransactionAdded) event()      {}
+func (TransactionUpdated) event()    {}
+func (AccountBalanceChanged) event() {}
+func (EntityDeleted) event()         {}
+
+// Option configures a Watcher.
+type Option func(*Watcher)
+
+// WithInterval sets the delay between successful polls. The default is 30
+// seconds.
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithBackoff sets the delay after a failed poll. The delay starts at initial,
+// doubles after each consecutive failure, and never exceeds maxDelay. The
+// defaults are 5 seconds and 5 minutes.
+func WithBackoff(initial, maxDelay time.Duration) Option {
+	return func(w *Watcher) {
+		w.backoffInitial = initial
+		w.backoffMax = maxDelay
+	}
+}
+
+// WithCursor sets where the watcher keeps its server timestamp. The default is
+// an in-memory cursor that starts with a full synchronization.
+func WithCursor(cursor Cursor) Option {
+	return func(w *Watcher) {
+		w.cursor = cursor
+	}
+}
+
+// WithLogger sets the logger used to report failed polls. Failures are
+// discarded by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(w *Watcher) {
+		w.logger = logger
+	}
+}
+
+// Watcher polls for changes. A Watcher must not be run more than once at the
+// same time.
+type Watcher struct {
+	syncer         Syncer
+	interval       time.Duration
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+	cursor         Cursor
+	logger         *slog.Logger
+
+	baselined    bool
+	transactions map[string]bool
+	balances     map[string]*float64
+}
+
+// New creates a Watcher that polls syncer.
+func New(syncer Syncer, opts ...Option) (*Watcher, error) {
+	w := &Watcher{
+		syncer:         syncer,
+		interval:       30 * time.Second,
+		backoffInitial: 5 * time.Second,
+		backoffMax:     5 * time.Minute,
+		cursor:         &MemoryCursor{},
+		logger:         slog.New(slog.DiscardHandler),
+		transactions:   make(map[string]bool),
+		balances:       make(map[string]*float64),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if syncer == nil {
+		return nil, errors.New(errors.ErrInvalidRequest, "syncer is nil", nil)
+	}
+	if w.interval <= 0 {
+		return nil, errors.New(errors.ErrInvalidRequest, "poll interval must be positive", nil)
+	}
+	if w.backoffInitial <= 0 || w.backoffMax < w.backoffInitial {
+		return nil, errors.New(errors.ErrInvalidRequest,
+			"backoff must be positive and the maximum must not be less than the initial delay", nil)
+	}
+	if w.cursor == nil {
+		return nil, errors.New(errors.ErrInvalidRequest, "cursor is nil", nil)
+	}
+	if w.logger == nil {
+		return nil, errors.New(errors.ErrInvalidRequest, "logger is nil", nil)
+	}
+
+	return w, nil
+}
+
+// Run polls until ctx is canceled and calls handle for every event in the
+// order the changes were received. handle runs on the polling goroutine, so a
+// slow handler delays the next poll.
+//
+// When the cursor is empty, the first poll performs a full synchronization
+// that only records the current state and emits no events. Run returns nil
+// after ctx is canceled and an error when the cursor cannot be loaded.
+func (w *Watcher) Run(ctx context.Context, handle func(Event)) error {
+	cursor, err := w.cursor.Load(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	failures := 0
+	for {
+		next, err := w.poll(ctx, cursor, handle)
+		delay := w.interval
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			delay = w.backoff(failures)
+			failures++
+			w.logger.WarnContext(ctx, "ZenMoney watcher poll failed",
+				slog.Int64("server_timestamp", cursor),
+				slog.Int("consecutive_failures", failures),
+				slog.Duration("retry_in", delay),
+				slog.Any("error", err),
+			)
+		} else {
+			failures = 0
+		}
+		cursor = next
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Events runs the watcher in a new goroutine and delivers events on the
+// returned channel. The channel is closed after ctx is canceled or the cursor
+// cannot be loaded; use Run to observe that error.
+func (w *Watcher) Events(ctx context.Context) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		_ = w.Run(ctx, func(event Event) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return events
+}
+
+// poll synchronizes once and returns the cursor to use for the next poll.
+// The cursor advances once events have been delivered even when saving it
+// fails, so events are not delivered twice by the same watcher.
+func (w *Watcher) poll(ctx context.Context, cursor int64, handle func(Event)) (int64, error) {
+	response, err := w.syncer.SyncSince(ctx, time.Unix(cursor, 0))
+	if err != nil {
+		return cursor, err
+	}
+
+	for _, event := range w.observe(response, cursor) {
+		if ctx.Err() != nil {
+			return cursor, ctx.Err()
+		}
+		handle(event)
+	}
+	if err := w.cursor.Save(ctx, response.ServerTimestamp); err != nil {
+		return response.ServerTimestamp, err
+	}
+
+	return response.ServerTimestamp, nil
+}
+
+// observe records response and returns the events it describes. The first
+// response after an empty cursor is a baseline and yields no events.
+func (w *Watcher) observe(response models.Response, cursor int64) []Event {
+	baseline := cursor == 0
+	var events []Event
+
+	for _, account := range response.Account {
+		previous, known := w.balances[account.ID]
+		w.balances[account.ID] = account.Balance
+		if baseline || (known && equalBalance(previous, account.Balance)) {
+			continue
+		}
+		events = append(events, AccountBalanceChanged{Account: account, Previous: previous})
+	}
+
+	for _, transaction := range response.Transaction {
+		seen := w.transactions[transaction.ID]
+		if transaction.Deleted {
+			delete(w.transactions, transaction.ID)
+			if !baseline {
+				events = append(events, EntityDeleted{Deletion: models.Deletion{
+					ID:     transaction.ID,
+					Object: string(models.EntityTypeTransaction),
+					Stamp:  transaction.Changed,
+					User:   transaction.User,
+				}})
+			}
+			continue
+		}
+		w.transactions[transaction.ID] = true
+		if baseline {
+			continue
+		}
+		// Without a baseline the watcher cannot know which transactions
+		// existed before it started, so creation time decides.
+		if !seen && (w.baselined || transaction.Created >= cursor) {
+			events = append(events, TransactionAdded{Transaction: transaction})
+		} else {
+			events = append(events, TransactionUpdated{Transaction: transaction})
+		}
+	}
+
+	for _, deletion := range response.Deletion {
+		switch models.EntityType(deletion.Object) {
+		case models.EntityTypeTransaction:
+			delete(w.transactions, deletion.ID)
+		case models.EntityTypeAccount:
+			delete(w.balances, deletion.ID)
+		}
+		if !baseline {
+			events = append(events, EntityDeleted{Deletion: deletion})
+		}
+	}
+
+	if baseline {
+		w.baselined = true
+	}
+
+	return events
+}
+
+func (w *Watcher) backoff(failures int) time.Duration {
+	delay := w.backoffInitial
+	for range failures {
+		if delay >= w.backoffMax/2 {
+			return w.backoffMax
+		}
+		delay *= 2
+	}
+
+	return min(delay, w.backoffMax)
+}
+
+func equalBalance(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
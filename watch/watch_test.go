@@ -0,0 +1,227 @@
+package watch_test
+
+import (
+	"bytes"
+	"context"
+	stdErrors "errors"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/nemirlev/zenmoney-go-sdk/v3/errors"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/models"
+	"github.com/nemirlev/zenmoney-go-sdk/v3/watch"
+	"github.com/stretchr/testify/require"
+)
+
+type result struct {
+	response models.Response
+	err      error
+}
+
+// scriptedSyncer returns results in order and then repeats an empty response
+// with the last server timestamp.
+type scriptedSyncer struct {
+	mu       sync.Mutex
+	results  []result
+	lastSync []int64
+	last     int64
+}
+
+func (s *scriptedSyncer) SyncSince(_ context.Context, lastSync time.Time) (models.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSync = append(s.lastSync, lastSync.Unix())
+	if len(s.results) == 0 {
+		return models.Response{ServerTimestamp: s.last}, nil
+	}
+	next := s.results[0]
+	s.results = s.results[1:]
+	if next.err == nil {
+		s.last = next.response.ServerTimestamp
+	}
+	return next.response, next.err
+}
+
+func (s *scriptedSyncer) calls() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]int64(nil), s.lastSync...)
+}
+
+func collect(t *testing.T, events <-chan watch.Event, count int) []watch.Event {
+	t.Helper()
+	var received []watch.Event
+	timeout := time.After(5 * time.Second)
+	for len(received) < count {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case <-timeout:
+			t.Fatalf("received %d of %d events", len(received), count)
+		}
+	}
+
+	return received
+}
+
+func TestWatcherEmitsChangesAfterBaseline(t *testing.T) {
+	syncer := &scriptedSyncer{results: []result{
+		{response: models.Response{
+			ServerTimestamp: 100,
+			Account:         []models.Account{{ID: "acc-1", Balance: ptr(10.0)}, {ID: "acc-2", Balance: ptr(5.0)}},
+			Transaction:     []models.Transaction{{ID: "tx-1", Outcome: 10}},
+		}},
+		{response: models.Response{
+			ServerTimestamp: 200,
+			Account:         []models.Account{{ID: "acc-1", Balance: ptr(15.0)}, {ID: "acc-2", Balance: ptr(5.0)}},
+			Transaction: []models.Transaction{
+				{ID: "tx-1", Outcome: 12},
+				{ID: "tx-2", Outcome: 5, Created: 10},
+				{ID: "tx-3", Deleted: true, Changed: 190, User: 1},
+			},
+			Deletion: []models.Deletion{{ID: "tag-1", Object: string(models.EntityTypeTag), Stamp: 195, User: 1}},
+		}},
+	}}
+	cursor := &watch.MemoryCursor{}
+	w, err := watch.New(syncer, watch.WithInterval(time.Millisecond), watch.WithCursor(cursor))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := collect(t, w.Events(ctx), 5)
+
+	require.Equal(t, []watch.Event{
+		watch.AccountBalanceChanged{Account: models.Account{ID: "acc-1", Balance: ptr(15.0)}, Previous: ptr(10.0)},
+		watch.TransactionUpdated{Transaction: models.Transaction{ID: "tx-1", Outcome: 12}},
+		watch.TransactionAdded{Transaction: models.Transaction{ID: "tx-2", Outcome: 5, Created: 10}},
+		watch.EntityDeleted{Deletion: models.Deletion{ID: "tx-3", Object: string(models.EntityTypeTransaction), Stamp: 190, User: 1}},
+		watch.EntityDeleted{Deletion: models.Deletion{ID: "tag-1", Object: string(models.EntityTypeTag), Stamp: 195, User: 1}},
+	}, events)
+	require.Equal(t, []int64{0, 100}, syncer.calls()[:2])
+	require.Eventually(t, func() bool {
+		timestamp, err := cursor.Load(ctx)
+		return err == nil && timestamp == 200
+	}, time.Second, time.Millisecond)
+}
+
+func TestWatcherResumesFromSavedCursor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cursor := watch.NewFileCursor(filepath.Join(t.TempDir(), "cursor"))
+	require.NoError(t, cursor.Save(ctx, 100))
+	syncer := &scriptedSyncer{results: []result{
+		{response: models.Response{
+			ServerTimestamp: 200,
+			Account:         []models.Account{{ID: "acc-1", Balance: ptr(15.0)}},
+			Transaction: []models.Transaction{
+				{ID: "tx-new", Created: 150},
+				{ID: "tx-old", Created: 50},
+			},
+		}},
+	}}
+	w, err := watch.New(syncer, watch.WithInterval(time.Millisecond), watch.WithCursor(cursor))
+	require.NoError(t, err)
+
+	events := collect(t, w.Events(ctx), 3)
+
+	require.Equal(t, []watch.Event{
+		watch.AccountBalanceChanged{Account: models.Account{ID: "acc-1", Balance: ptr(15.0)}},
+		watch.TransactionAdded{Transaction: models.Transaction{ID: "tx-new", Created: 150}},
+		watch.TransactionUpdated{Transaction: models.Transaction{ID: "tx-old", Created: 50}},
+	}, events)
+	require.Equal(t, int64(100), syncer.calls()[0])
+	require.Eventually(t, func() bool {
+		timestamp, err := cursor.Load(ctx)
+		return err == nil && timestamp == 200
+	}, time.Second, time.Millisecond)
+}
+
+func TestWatcherBacksOffAfterErrors(t *testing.T) {
+	syncer := &scriptedSyncer{results: []result{
+		{response: models.Response{ServerTimestamp: 100}},
+		{err: stdErrors.New("network down")},
+		{err: stdErrors.New("network down")},
+		{response: models.Response{ServerTimestamp: 200, Transaction: []models.Transaction{{ID: "tx-1", Created: 150}}}},
+	}}
+	var logs bytes.Buffer
+	var logsMu sync.Mutex
+	logger := slog.New(slog.NewTextHandler(writerFunc(func(p []byte) (int, error) {
+		logsMu.Lock()
+		defer logsMu.Unlock()
+		return logs.Write(p)
+	}), nil))
+	w, err := watch.New(syncer,
+		watch.WithInterval(time.Millisecond),
+		watch.WithBackoff(time.Millisecond, 4*time.Millisecond),
+		watch.WithLogger(logger),
+	)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := collect(t, w.Events(ctx), 1)
+
+	require.Equal(t, []watch.Event{watch.TransactionAdded{Transaction: models.Transaction{ID: "tx-1", Created: 150}}}, events)
+	require.Equal(t, []int64{0, 100, 100, 100}, syncer.calls()[:4])
+	logsMu.Lock()
+	defer logsMu.Unlock()
+	require.Contains(t, logs.String(), "ZenMoney watcher poll failed")
+	require.Contains(t, logs.String(), "consecutive_failures=2")
+}
+
+func TestWatcherRunStopsOnCancel(t *testing.T) {
+	w, err := watch.New(&scriptedSyncer{}, watch.WithInterval(time.Hour))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() { done <- w.Run(ctx, func(watch.Event) {}) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewValidatesOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		syncer watch.Syncer
+		opts   []watch.Option
+	}{
+		{name: "nil syncer"},
+		{name: "zero interval", syncer: &scriptedSyncer{}, opts: []watch.Option{watch.WithInterval(0)}},
+		{name: "zero backoff", syncer: &scriptedSyncer{}, opts: []watch.Option{watch.WithBackoff(0, time.Second)}},
+		{name: "inverted backoff", syncer: &scriptedSyncer{}, opts: []watch.Option{watch.WithBackoff(time.Minute, time.Second)}},
+		{name: "nil cursor", syncer: &scriptedSyncer{}, opts: []watch.Option{watch.WithCursor(nil)}},
+		{name: "nil logger", syncer: &scriptedSyncer{}, opts: []watch.Option{watch.WithLogger(nil)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := watch.New(tt.syncer, tt.opts...)
+
+			var sdkErr *sdkerrors.Error
+			require.ErrorAs(t, err, &sdkErr)
+			require.Equal(t, sdkerrors.ErrInvalidRequest, sdkErr.Code)
+		})
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}